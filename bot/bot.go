@@ -0,0 +1,130 @@
+// Package bot plays games without a human, to measure how rules play out over many games.
+package bot
+
+import (
+	"github.com/borogk/hsweeper/game"
+)
+
+type (
+	// Player decides on the next action of a game, seeing only what a human would see.
+	// A new player is created for every game, so it may remember things between actions.
+	Player interface {
+		Play(board Board) Action
+	}
+
+	// ActionKind is what the player does to a cell.
+	ActionKind byte
+
+	// Action is a single move of a player. Coordinates are 0-based.
+	Action struct {
+		Kind ActionKind
+		X, Y int
+	}
+
+	// Board is a read-only view of a game, hiding everything the player can't see.
+	Board struct {
+		game *game.Game
+	}
+
+	// CellView is what the player can see of a cell. Adjacent mines and hearts are only known once revealed.
+	CellView struct {
+		Revealed      bool
+		Flagged       bool
+		Exploded      bool
+		Heart         bool
+		AdjacentMines int
+	}
+)
+
+const (
+	// ActionReveal reveals the cell.
+	ActionReveal ActionKind = iota
+	// ActionFlag toggles flag on the cell.
+	ActionFlag
+	// ActionChord reveals around the number with enough flags.
+	ActionChord
+	// ActionPickup picks up the heart.
+	ActionPickup
+)
+
+// NewBoard creates a read-only view of the game.
+func NewBoard(g *game.Game) Board {
+	return Board{game: g}
+}
+
+// Width returns the board width.
+func (b Board) Width() int {
+	return b.game.Width()
+}
+
+// Height returns the board height.
+func (b Board) Height() int {
+	return b.game.Height()
+}
+
+// Status returns the game status.
+func (b Board) Status() game.Status {
+	return b.game.Status()
+}
+
+// MinesRemaining returns how many mines are left, taking flags into account.
+func (b Board) MinesRemaining() int {
+	return b.game.MinesRemaining()
+}
+
+// LivesRemaining returns how many lives are left.
+func (b Board) LivesRemaining() int {
+	return b.game.LivesRemaining()
+}
+
+// Cell returns what can be seen of the cell.
+func (b Board) Cell(x, y int) CellView {
+	cell := b.game.Cell(x, y)
+	if !cell.IsRevealed() {
+		return CellView{Flagged: cell.IsFlagged()}
+	}
+
+	return CellView{
+		Revealed:      true,
+		Exploded:      cell.IsExploded(),
+		Heart:         cell.IsHeart(),
+		AdjacentMines: cell.AdjacentMines(),
+	}
+}
+
+// Solve deduces which unrevealed cells are certainly safe or certainly mines.
+func (b Board) Solve() *game.Solution {
+	return b.game.Solve()
+}
+
+// Play lets the player make moves until the game is finished. Gives up after many more actions than there are cells,
+// in case the player keeps doing something that has no effect. Returns whether the game was finished.
+func Play(g *game.Game, player Player) bool {
+	board := NewBoard(g)
+	for range 4 * g.Width() * g.Height() {
+		if g.IsFinished() {
+			return true
+		}
+		apply(g, player.Play(board))
+	}
+	return g.IsFinished()
+}
+
+// Applies the action to the game, actions outside the board are ignored.
+func apply(g *game.Game, action Action) {
+	x, y := action.X, action.Y
+	if g.IsOutOfBounds(x, y) {
+		return
+	}
+
+	switch action.Kind {
+	case ActionReveal:
+		g.Reveal(x, y)
+	case ActionFlag:
+		g.ToggleFlag(x, y)
+	case ActionChord:
+		g.AdvancedReveal(x, y)
+	case ActionPickup:
+		g.Pickup(x, y)
+	}
+}
@@ -0,0 +1,68 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/borogk/hsweeper/game"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBoard_Cell(t *testing.T) {
+	g := game.NewSeededGame(game.Rules{Width: 9, Height: 9, Mines: 10, Lives: 1}, 5)
+	g.Reveal(4, 4)
+	board := NewBoard(g)
+
+	for y := range 9 {
+		for x := range 9 {
+			cell, view := g.Cell(x, y), board.Cell(x, y)
+			if !cell.IsRevealed() && view != (CellView{}) {
+				t.Errorf("unrevealed cell %d,%d shows %+v", x, y, view)
+			}
+			if cell.IsRevealed() && view.AdjacentMines != cell.AdjacentMines() {
+				t.Errorf("revealed cell %d,%d shows %d mines instead of %d", x, y, view.AdjacentMines, cell.AdjacentMines())
+			}
+		}
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	rules := game.Rules{Width: 16, Height: 16, Mines: 40, Hearts: 2, Lives: 1}
+
+	t.Run("plays every game to the end", func(t *testing.T) {
+		report := Simulate(rules, 50, 1, 4, NewSolverPlayer)
+		if report.Games != 50 || report.Unfinished != 0 {
+			t.Errorf("unexpected report %+v", report)
+		}
+		if report.Won == 0 {
+			t.Errorf("solver bot should win some games of Classic Medium")
+		}
+
+		total := 0
+		for _, games := range report.Hearts {
+			total += games
+		}
+		if total != 50 {
+			t.Errorf("hearts distribution covers %d games instead of 50", total)
+		}
+	})
+
+	t.Run("gives the same report regardless of workers", func(t *testing.T) {
+		if diff := cmp.Diff(Simulate(rules, 20, 7, 1, NewSolverPlayer), Simulate(rules, 20, 7, 8, NewSolverPlayer)); diff != "" {
+			t.Errorf("reports differ\n%s", diff)
+		}
+	})
+}
+
+// Player, which only ever tries to reveal a cell outside the board.
+type idlePlayer struct{}
+
+func (idlePlayer) Play(board Board) Action {
+	return Action{Kind: ActionReveal, X: -1, Y: -1}
+}
+
+func TestPlay_GivesUp(t *testing.T) {
+	g := game.NewSeededGame(game.Rules{Width: 9, Height: 9, Mines: 10, Lives: 1}, 5)
+	if Play(g, idlePlayer{}) {
+		t.Errorf("game is finished without a single move")
+	}
+}
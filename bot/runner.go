@@ -0,0 +1,93 @@
+package bot
+
+import (
+	"sync"
+
+	"github.com/borogk/hsweeper/game"
+)
+
+type (
+	// Report sums up a batch of games played by a bot.
+	Report struct {
+		Games      int
+		Won        int
+		Unfinished int   // Games the player gave up on, counted as not won
+		LivesUsed  int   // Total over all games
+		Hearts     []int // Amount of games by hearts collected in them, indexed by the amount of hearts
+	}
+
+	// Outcome of a single game.
+	outcome struct {
+		won       bool
+		finished  bool
+		livesUsed int
+		hearts    int
+	}
+)
+
+// Simulate plays games with the rules, each by a new player, spread over workers running in parallel.
+// Game i gets seed+i, so the same seed always gives the same report regardless of the amount of workers.
+func Simulate(rules game.Rules, games int, seed int64, workers int, newPlayer func() Player) Report {
+	outcomes := make([]outcome, games)
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for range max(workers, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indices {
+				outcomes[i] = playGame(game.NewSeededGame(rules, seed+int64(i)), newPlayer())
+			}
+		}()
+	}
+	for i := range games {
+		indices <- i
+	}
+	close(indices)
+	wg.Wait()
+
+	report := Report{Games: games}
+	for _, o := range outcomes {
+		if o.won {
+			report.Won++
+		}
+		if !o.finished {
+			report.Unfinished++
+		}
+		report.LivesUsed += o.livesUsed
+		for len(report.Hearts) <= o.hearts {
+			report.Hearts = append(report.Hearts, 0)
+		}
+		report.Hearts[o.hearts]++
+	}
+	return report
+}
+
+// Plays a single game to the end.
+func playGame(g *game.Game, player Player) outcome {
+	finished := Play(g, player)
+	return outcome{
+		won:       g.Status() == game.StatusWon,
+		finished:  finished,
+		livesUsed: g.LivesUsed(),
+		hearts:    g.HeartsCollected(),
+	}
+}
+
+// WinRate returns share of games won, from 0 to 1.
+func (r Report) WinRate() float64 {
+	if r.Games == 0 {
+		return 0
+	}
+
+	return float64(r.Won) / float64(r.Games)
+}
+
+// AverageLivesUsed returns lives used per game.
+func (r Report) AverageLivesUsed() float64 {
+	if r.Games == 0 {
+		return 0
+	}
+
+	return float64(r.LivesUsed) / float64(r.Games)
+}
@@ -0,0 +1,131 @@
+package bot
+
+import (
+	"github.com/borogk/hsweeper/game"
+)
+
+// SolverPlayer is the reference bot. It picks up every heart, reveals cells proven safe by the solver, and when
+// there are none, guesses the cell least likely to be a mine by a rough local estimate. Flags are never placed.
+type SolverPlayer struct {
+	safe []int // Safe cells found by the last solve, which are not revealed yet
+}
+
+// NewSolverPlayer creates the reference bot.
+func NewSolverPlayer() Player {
+	return &SolverPlayer{}
+}
+
+func (p *SolverPlayer) Play(board Board) Action {
+	width, height := board.Width(), board.Height()
+	if board.Status() == game.StatusReady {
+		return Action{Kind: ActionReveal, X: width / 2, Y: height / 2}
+	}
+
+	for y := range height {
+		for x := range width {
+			if board.Cell(x, y).Heart {
+				return Action{Kind: ActionPickup, X: x, Y: y}
+			}
+		}
+	}
+
+	// Safe cells stay safe, so solving again is only needed once all of them are revealed
+	for len(p.safe) > 0 {
+		location := p.safe[0]
+		p.safe = p.safe[1:]
+		if x, y := location%width, location/width; !board.Cell(x, y).Revealed {
+			return Action{Kind: ActionReveal, X: x, Y: y}
+		}
+	}
+
+	solution := board.Solve()
+	if p.safe = solution.SafeLocations(); len(p.safe) > 0 {
+		location := p.safe[0]
+		p.safe = p.safe[1:]
+		return Action{Kind: ActionReveal, X: location % width, Y: location / width}
+	}
+
+	x, y := p.guess(board, solution)
+	return Action{Kind: ActionReveal, X: x, Y: y}
+}
+
+// Finds the undecided cell least likely to be a mine. Cells next to numbers take the worst share of mines left
+// around any of the numbers, other cells take the share of mines left over the whole board.
+func (p *SolverPlayer) guess(board Board, solution *game.Solution) (guessX, guessY int) {
+	width, height := board.Width(), board.Height()
+	undecided, knownMines := 0, 0
+	for y := range height {
+		for x := range width {
+			switch solution.Knowledge(x, y) {
+			case game.KnowledgeUnknown:
+				undecided++
+			case game.KnowledgeMine:
+				knownMines++
+			}
+		}
+	}
+
+	// Flags are never placed, so the counter shows all mines left
+	density := float64(board.MinesRemaining()-knownMines) / float64(max(undecided, 1))
+	risks := make([]float64, width*height)
+	for i := range risks {
+		risks[i] = -1
+	}
+	for y := range height {
+		for x := range width {
+			cell := board.Cell(x, y)
+			if !cell.Revealed {
+				continue
+			}
+
+			mines, cells := cell.AdjacentMines, 0
+			forAdjacent(width, height, x, y, func(ax, ay int) {
+				switch solution.Knowledge(ax, ay) {
+				case game.KnowledgeUnknown:
+					cells++
+				case game.KnowledgeMine:
+					mines--
+				}
+			})
+			if cells == 0 {
+				continue
+			}
+
+			risk := float64(mines) / float64(cells)
+			forAdjacent(width, height, x, y, func(ax, ay int) {
+				if solution.Knowledge(ax, ay) == game.KnowledgeUnknown {
+					risks[ax+ay*width] = max(risks[ax+ay*width], risk)
+				}
+			})
+		}
+	}
+
+	best := -1.0
+	for y := range height {
+		for x := range width {
+			if solution.Knowledge(x, y) != game.KnowledgeUnknown {
+				continue
+			}
+
+			risk := risks[x+y*width]
+			if risk < 0 {
+				risk = density
+			}
+			if best < 0 || risk < best {
+				best, guessX, guessY = risk, x, y
+			}
+		}
+	}
+	return guessX, guessY
+}
+
+// Calls f for every cell adjacent to the given one.
+func forAdjacent(width, height, x, y int, f func(x, y int)) {
+	for ay := max(y-1, 0); ay <= min(y+1, height-1); ay++ {
+		for ax := max(x-1, 0); ax <= min(x+1, width-1); ax++ {
+			if ax != x || ay != y {
+				f(ax, ay)
+			}
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/borogk/hsweeper/bot"
+	"github.com/borogk/hsweeper/game"
+	"github.com/borogk/hsweeper/headless"
+	"github.com/borogk/hsweeper/ui"
+)
+
+func runStats(args []string) error {
+	fs := newFlagSet("stats", "")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	return ui.WriteStats(os.Stdout, game.DefaultStatsPath())
+}
+
+func runReplay(args []string) error {
+	fs := newFlagSet("replay", "[flags] [FILE]")
+	var uiFlags uiFlags
+	uiFlags.register(fs)
+	if err := parseFlags(fs, args, 1); err != nil {
+		return err
+	}
+
+	options, err := uiFlags.options()
+	if err != nil {
+		return err
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	var snapshot *game.Snapshot
+	if fs.NArg() == 1 {
+		data, err := os.ReadFile(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		if snapshot, err = game.DecodeSnapshot(data); err != nil {
+			return fmt.Errorf("broken saved game %s: %w", fs.Arg(0), err)
+		}
+	} else if snapshot = lastRecordedGame(); snapshot == nil {
+		return errors.New("no finished games are recorded yet")
+	}
+
+	ui.NewUiWithReplay(snapshot, options).Loop()
+	return nil
+}
+
+// Returns the final board of the last finished game, nil if there is none.
+func lastRecordedGame() *game.Snapshot {
+	records := game.LoadStats(game.DefaultStatsPath()).Records
+	for i := len(records) - 1; i >= 0; i-- {
+		if records[i].Board != nil {
+			return records[i].Board
+		}
+	}
+	return nil
+}
+
+func runSolve(args []string) error {
+	fs := newFlagSet("solve", "CODE")
+	if err := parseFlags(fs, args, len(args)); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return usageError(fs, "a board code is required")
+	}
+
+	// Codes may be wrapped over multiple arguments, just like over multiple lines
+	g, err := game.DecodeCode(strings.Join(fs.Args(), ""))
+	if err != nil {
+		return err
+	}
+
+	fmt.Print(formatSolution(g, g.Solve()))
+	return nil
+}
+
+// Draws the board as text with what is known about every unrevealed cell, followed by a legend.
+func formatSolution(g *game.Game, solution *game.Solution) string {
+	var b strings.Builder
+	counts := map[game.Knowledge]int{}
+	for y := range g.Height() {
+		for x := range g.Width() {
+			knowledge := solution.Knowledge(x, y)
+			counts[knowledge]++
+
+			symbol := "#"
+			switch knowledge {
+			case game.KnowledgeRevealed:
+				symbol = "."
+				if cell := g.Cell(x, y); cell.IsMine() {
+					symbol = "x"
+				} else if cell.AdjacentMines() > 0 {
+					symbol = fmt.Sprint(cell.AdjacentMines())
+				}
+			case game.KnowledgeSafe:
+				symbol = "o"
+			case game.KnowledgeMine:
+				symbol = "*"
+			}
+			b.WriteString(symbol)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("\n")
+	if g.Status() == game.StatusReady {
+		b.WriteString("Nothing is known before the first reveal, export the code with progress.\n")
+	}
+	fmt.Fprintf(&b, "o  safe     %d\n", counts[game.KnowledgeSafe])
+	fmt.Fprintf(&b, "*  mine     %d\n", counts[game.KnowledgeMine])
+	fmt.Fprintf(&b, "#  unknown  %d\n", counts[game.KnowledgeUnknown])
+	b.WriteString("x  exploded mine, .  revealed blank\n")
+	return b.String()
+}
+
+func runGenerate(args []string) error {
+	fs := newFlagSet("generate", "[flags]")
+	var rulesFlags rulesFlags
+	rulesFlags.register(fs)
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	rules, err := rulesFlags.rules(fs)
+	if err != nil {
+		return err
+	} else if rules == nil {
+		return usageError(fs, "pick a -mode or give -width, -height and -mines")
+	} else if rules.Width == 0 {
+		return fmt.Errorf("%s is sized by the screen, give -width and -height", rules.Mode)
+	}
+	if err := (ui.Options{Rules: rules}).Validate(); err != nil {
+		return err
+	}
+
+	g := game.NewGameWithRules(*rules)
+	if rulesFlags.seed != 0 {
+		g = game.NewSeededGame(*rules, rulesFlags.seed)
+	}
+	fmt.Println(g.EncodeCode(false))
+	return nil
+}
+
+func runHeadless(args []string) error {
+	fs := newFlagSet("headless", "[flags]")
+	var rulesFlags rulesFlags
+	rulesFlags.register(fs)
+	code := fs.String("code", "", "play the board from a shareable code")
+	jsonFormat := fs.Bool("json", false, "write responses as JSON lines instead of plain text")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	rules, err := rulesFlags.rules(fs)
+	if err != nil {
+		return err
+	}
+
+	var g *game.Game
+	if *code != "" {
+		if rules != nil || rulesFlags.seed != 0 {
+			return errors.New("a code already has the rules and the seed")
+		}
+		if g, err = game.DecodeCode(*code); err != nil {
+			return err
+		}
+	} else {
+		if rules == nil {
+			expert, _ := ui.FindMode("expert")
+			rules = &expert
+		} else if rules.Width == 0 {
+			return fmt.Errorf("%s is sized by the screen, give -width and -height", rules.Mode)
+		}
+		if err := (ui.Options{Rules: rules}).Validate(); err != nil {
+			return err
+		}
+
+		g = game.NewGameWithRules(*rules)
+		if rulesFlags.seed != 0 {
+			g = game.NewSeededGame(*rules, rulesFlags.seed)
+		}
+	}
+
+	format := headless.FormatText
+	if *jsonFormat {
+		format = headless.FormatJSON
+	}
+	return headless.NewSession(g, format).Run(os.Stdin, os.Stdout)
+}
+
+func runSimulate(args []string) error {
+	fs := newFlagSet("simulate", "[flags]")
+	var rulesFlags rulesFlags
+	rulesFlags.register(fs)
+	games := fs.Int("games", 100, "amount of games to play")
+	workers := fs.Int("workers", runtime.NumCPU(), "amount of games played in parallel")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	rules, err := rulesFlags.rules(fs)
+	if err != nil {
+		return err
+	} else if rules == nil {
+		return usageError(fs, "pick a -mode or give -width, -height and -mines")
+	} else if rules.Width == 0 {
+		return fmt.Errorf("%s is sized by the screen, give -width and -height", rules.Mode)
+	}
+	if err := (ui.Options{Rules: rules}).Validate(); err != nil {
+		return err
+	}
+	if *games < 1 || *workers < 1 {
+		return usageError(fs, "games and workers must be positive")
+	}
+
+	// Seeds of the batch follow one another, so a random start is enough to get a random batch
+	seed := rulesFlags.seed
+	if seed == 0 {
+		seed = game.NewGameWithRules(*rules).Seed()
+	}
+
+	report := bot.Simulate(*rules, *games, seed, *workers, bot.NewSolverPlayer)
+	fmt.Print(formatReport(*rules, seed, report))
+	return nil
+}
+
+// Formats the simulation report as a few lines of text.
+func formatReport(rules game.Rules, seed int64, report bot.Report) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Rules       %s, %dx%d, %d mines, %d hearts (%s), %d lives\n",
+		rules.Name(), rules.Width, rules.Height, rules.Mines, rules.Hearts, rules.HeartSpawning, rules.Lives)
+	fmt.Fprintf(&b, "Seeds       %d to %d\n", seed, seed+int64(report.Games-1))
+	fmt.Fprintf(&b, "Won         %d of %d, %.1f%%\n", report.Won, report.Games, report.WinRate()*100)
+	fmt.Fprintf(&b, "Lives used  %.2f on average\n", report.AverageLivesUsed())
+	if report.Unfinished > 0 {
+		fmt.Fprintf(&b, "Unfinished  %d\n", report.Unfinished)
+	}
+	b.WriteString("Hearts      Games\n")
+	for hearts, games := range report.Hearts {
+		fmt.Fprintf(&b, "%6d  %9d  %5.1f%%\n", hearts, games, float64(games)/float64(report.Games)*100)
+	}
+	return b.String()
+}
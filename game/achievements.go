@@ -0,0 +1,209 @@
+package game
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"time"
+)
+
+type (
+	// Achievement is a goal reached by finishing games in a particular way.
+	Achievement struct {
+		ID          string
+		Name        string
+		Description string
+		Goal        int
+		progress    func(stats *Stats) int
+	}
+
+	// Achievements keeps track of unlocked achievements.
+	Achievements struct {
+		Unlocked map[string]time.Time
+	}
+)
+
+// AllAchievements lists every achievement in order of display.
+var AllAchievements = []Achievement{
+	{
+		ID:          "first-win",
+		Name:        "First steps",
+		Description: "Win any game",
+		Goal:        1,
+		progress: anyRecord(func(record GameRecord) bool {
+			return record.Result == StatusWon
+		}),
+	},
+	{
+		ID:          "second-chance",
+		Name:        "Second chance",
+		Description: "Win a game after losing a life",
+		Goal:        1,
+		progress: anyRecord(func(record GameRecord) bool {
+			return record.Result == StatusWon && record.LivesUsed > 0
+		}),
+	},
+	{
+		ID:          "heart-collector",
+		Name:        "Heart collector",
+		Description: "Collect 5 hearts in one game",
+		Goal:        5,
+		progress: func(stats *Stats) int {
+			best := 0
+			for _, record := range stats.Records {
+				if !record.Retry {
+					best = max(best, record.HeartsCollected)
+				}
+			}
+			return best
+		},
+	},
+	{
+		ID:          "efficient",
+		Name:        "Efficient",
+		Description: "Win a game in under 3BV+10 clicks",
+		Goal:        1,
+		progress: anyRecord(func(record GameRecord) bool {
+			return record.Result == StatusWon && record.Clicks < record.ThreeBV+10
+		}),
+	},
+	{
+		ID:          "quick-easy",
+		Name:        "Quick and easy",
+		Description: "Win Classic Easy in under 10 seconds",
+		Goal:        1,
+		progress: anyRecord(func(record GameRecord) bool {
+			return record.Result == StatusWon && record.Rules.Name() == ModeClassicEasy && record.Time < 10*time.Second
+		}),
+	},
+	{
+		ID:          "no-flags",
+		Name:        "No flags attached",
+		Description: "Win Classic Expert without placing a flag",
+		Goal:        1,
+		progress: anyRecord(func(record GameRecord) bool {
+			return record.Result == StatusWon && record.Rules.Name() == ModeClassicExpert && !usedFlags(record)
+		}),
+	},
+	{
+		ID:          "untouchable",
+		Name:        "Untouchable",
+		Description: "Win H-Big without losing a life",
+		Goal:        1,
+		progress: anyRecord(func(record GameRecord) bool {
+			return record.Result == StatusWon && record.Rules.Name() == ModeBig && record.LivesUsed == 0
+		}),
+	},
+	{
+		ID:          "on-a-roll",
+		Name:        "On a roll",
+		Description: "Win 5 games of the same mode in a row",
+		Goal:        5,
+		progress: func(stats *Stats) int {
+			best := 0
+			for _, mode := range stats.Modes() {
+				best = max(best, stats.Summary(mode).BestStreak)
+			}
+			return best
+		},
+	},
+	{
+		ID:          "veteran",
+		Name:        "Veteran",
+		Description: "Finish 100 games",
+		Goal:        100,
+		progress: func(stats *Stats) int {
+			finished := 0
+			for _, record := range stats.Records {
+				if !record.Retry {
+					finished++
+				}
+			}
+			return finished
+		},
+	},
+}
+
+// DefaultAchievementsPath returns default achievements path.
+func DefaultAchievementsPath() string {
+	return DataPath("achievements.json")
+}
+
+// LoadAchievements loads achievements from disk, missing or broken file results in nothing unlocked.
+func LoadAchievements(path string) *Achievements {
+	achievements := &Achievements{}
+	if data, err := os.ReadFile(path); err == nil {
+		if json.Unmarshal(data, achievements) != nil {
+			achievements = &Achievements{}
+		}
+	}
+
+	if achievements.Unlocked == nil {
+		achievements.Unlocked = make(map[string]time.Time)
+	}
+
+	return achievements
+}
+
+// Save persists achievements on disk.
+func (a *Achievements) Save(filePath string) error {
+	if err := os.MkdirAll(path.Dir(filePath), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, 0600)
+}
+
+// IsUnlocked checks if the achievement has been unlocked.
+func (a *Achievements) IsUnlocked(id string) bool {
+	_, ok := a.Unlocked[id]
+	return ok
+}
+
+// Update unlocks all achievements reached according to statistics. Returns only newly unlocked ones.
+func (a *Achievements) Update(stats *Stats) []Achievement {
+	unlocked := make([]Achievement, 0)
+	for _, achievement := range AllAchievements {
+		if !a.IsUnlocked(achievement.ID) && achievement.Progress(stats) >= achievement.Goal {
+			a.Unlocked[achievement.ID] = time.Now()
+			unlocked = append(unlocked, achievement)
+		}
+	}
+	return unlocked
+}
+
+// Progress returns how close statistics are to the goal, capped at the goal.
+func (a Achievement) Progress(stats *Stats) int {
+	return min(a.progress(stats), a.Goal)
+}
+
+// Makes progress of a single-game achievement, reached as soon as any record matches. Retries don't count.
+func anyRecord(predicate func(record GameRecord) bool) func(stats *Stats) int {
+	return func(stats *Stats) int {
+		for _, record := range stats.Records {
+			if !record.Retry && predicate(record) {
+				return 1
+			}
+		}
+		return 0
+	}
+}
+
+// Checks the move log for flags. Records without the final board are assumed to use flags, as it's unknown.
+func usedFlags(record GameRecord) bool {
+	if record.Board == nil {
+		return true
+	}
+
+	for _, move := range record.Board.Moves {
+		if move.Action == MoveToggleFlag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package game
+
+import (
+	"path"
+	"testing"
+	"time"
+)
+
+func TestAchievements_Update(t *testing.T) {
+	classicExpert := Rules{Mode: "Classic Expert", Width: 30, Height: 16, Mines: 99, Lives: 1}
+	win := func(rules Rules, moves ...Move) GameRecord {
+		return GameRecord{
+			Rules:   rules,
+			Result:  StatusWon,
+			Metrics: Metrics{Time: 100 * time.Second, Clicks: 200, ThreeBV: 150},
+			Board:   &Snapshot{Moves: moves},
+		}
+	}
+	ids := func(achievements []Achievement) []string {
+		result := make([]string, 0)
+		for _, achievement := range achievements {
+			result = append(result, achievement.ID)
+		}
+		return result
+	}
+
+	t.Run("unlocks achievements only once", func(t *testing.T) {
+		a := LoadAchievements(path.Join(t.TempDir(), "missing.json"))
+		stats := &Stats{}
+
+		stats.Add(GameRecord{Rules: classicExpert, Result: StatusLost, HeartsCollected: 3})
+		assertEquals(t, ids(a.Update(stats)), []string{})
+
+		stats.Add(win(classicExpert, Move{Action: MoveReveal}))
+		assertEquals(t, ids(a.Update(stats)), []string{"first-win", "no-flags"})
+		assertEquals(t, ids(a.Update(stats)), []string{})
+		assertEquals(t, a.IsUnlocked("no-flags"), true)
+		assertEquals(t, a.IsUnlocked("veteran"), false)
+	})
+
+	t.Run("requires no flags in the move log", func(t *testing.T) {
+		a := LoadAchievements(path.Join(t.TempDir(), "missing.json"))
+		stats := &Stats{}
+		stats.Add(win(classicExpert, Move{Action: MoveReveal}, Move{Action: MoveToggleFlag}))
+
+		assertEquals(t, ids(a.Update(stats)), []string{"first-win"})
+	})
+
+	t.Run("tracks progress towards goals", func(t *testing.T) {
+		stats := &Stats{}
+		for i := 0; i < 7; i++ {
+			stats.Add(GameRecord{Rules: classicExpert, Result: StatusLost, HeartsCollected: i % 4})
+		}
+		stats.Add(GameRecord{Rules: classicExpert, Result: StatusLost, HeartsCollected: 5, Retry: true})
+
+		for _, achievement := range AllAchievements {
+			switch achievement.ID {
+			case "heart-collector":
+				assertEquals(t, achievement.Progress(stats), 3)
+			case "veteran":
+				assertEquals(t, achievement.Progress(stats), 7)
+			}
+		}
+	})
+
+	t.Run("survives save and load", func(t *testing.T) {
+		achievementsPath := path.Join(t.TempDir(), "achievements.json")
+		a := LoadAchievements(achievementsPath)
+		stats := &Stats{}
+		stats.Add(win(classicExpert))
+		a.Update(stats)
+
+		assertSame(t, a.Save(achievementsPath), nil)
+		assertEquals(t, LoadAchievements(achievementsPath), a)
+	})
+}
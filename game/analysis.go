@@ -0,0 +1,56 @@
+package game
+
+import "slices"
+
+type (
+	// Verdict tells if the move, which lost the game, could have been avoided.
+	Verdict int
+
+	// LossAnalysis explains the move, which lost the game.
+	LossAnalysis struct {
+		Location  int // Cell with the mine, which ended the game
+		Verdict   Verdict
+		SafeCells int // Cells proven safe before the move, there were other options unless it's 0
+	}
+)
+
+const (
+	// VerdictForcedGuess means no cell was proven safe before the move, so guessing was unavoidable.
+	VerdictForcedGuess Verdict = iota
+	// VerdictUnforcedGuess means the cell couldn't be proven either way, but other cells were proven safe.
+	VerdictUnforcedGuess
+	// VerdictMistake means the cell was proven to have a mine.
+	VerdictMistake
+)
+
+// AnalyzeLoss replays the game up to the move, which lost it, and checks the position with the solver.
+// Returns false if the game isn't lost or can't be replayed.
+func (g *Game) AnalyzeLoss() (LossAnalysis, bool) {
+	if g.status != StatusLost {
+		return LossAnalysis{}, false
+	}
+
+	location := slices.IndexFunc(g.cells, func(c Cell) bool { return c.isMine && c.isExploded })
+	replay, err := NewReplay(g.Save())
+	if location < 0 || err != nil || replay.Len() == 0 {
+		return LossAnalysis{}, false
+	}
+
+	// The fatal move is the last one recorded, as finished games don't record any more
+	replay.Seek(replay.Len() - 1)
+	solution := replay.Game().Solve()
+	analysis := LossAnalysis{
+		Location:  location,
+		SafeCells: len(solution.SafeLocations()),
+	}
+
+	if solution.knowledge[location] == KnowledgeMine {
+		analysis.Verdict = VerdictMistake
+	} else if analysis.SafeCells > 0 {
+		analysis.Verdict = VerdictUnforcedGuess
+	} else {
+		analysis.Verdict = VerdictForcedGuess
+	}
+
+	return analysis, true
+}
@@ -0,0 +1,100 @@
+package game
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGame_AnalyzeLoss(t *testing.T) {
+	rules := Rules{Width: 8, Height: 8, Mines: 10, Lives: 1}
+
+	t.Run("finds forced guess on the first move", func(t *testing.T) {
+		rules := rules
+		rules.FirstClick = FirstClickUnprotected
+		g := seededGameWhere(rules, func(g *Game) bool {
+			return g.Reveal(0, 0) == RevealResultBlast
+		})
+
+		analysis, ok := g.AnalyzeLoss()
+		assertEquals(t, ok, true)
+		assertEquals(t, analysis, LossAnalysis{Location: 0, Verdict: VerdictForcedGuess, SafeCells: 0})
+	})
+
+	t.Run("finds mistake on revealing a proven mine", func(t *testing.T) {
+		var mine int
+		g := seededGameWhere(rules, func(g *Game) bool {
+			g.Reveal(4, 4)
+			mines := g.Solve().MineLocations()
+			if len(mines) == 0 || g.IsFinished() {
+				return false
+			}
+			mine = mines[0]
+			g.Reveal(mine%g.width, mine/g.width)
+			return true
+		})
+
+		analysis, ok := g.AnalyzeLoss()
+		assertEquals(t, ok, true)
+		assertEquals(t, analysis.Location, mine)
+		assertEquals(t, analysis.Verdict, VerdictMistake)
+	})
+
+	t.Run("finds unforced guess when other cells were proven safe", func(t *testing.T) {
+		var safeCells int
+		g := seededGameWhere(rules, func(g *Game) bool {
+			g.Reveal(4, 4)
+			solution := g.Solve()
+			safeCells = len(solution.SafeLocations())
+			if safeCells == 0 || g.IsFinished() {
+				return false
+			}
+			for i := range g.cells {
+				if g.cells[i].isMine && solution.knowledge[i] == KnowledgeUnknown {
+					g.Reveal(i%g.width, i/g.width)
+					return true
+				}
+			}
+			return false
+		})
+
+		analysis, ok := g.AnalyzeLoss()
+		assertEquals(t, ok, true)
+		assertEquals(t, analysis.Verdict, VerdictUnforcedGuess)
+		assertEquals(t, analysis.SafeCells, safeCells)
+	})
+
+	t.Run("has nothing to analyze unless the game is lost", func(t *testing.T) {
+		g := NewSeededGame(rules, 1)
+		g.Reveal(4, 4)
+
+		_, ok := g.AnalyzeLoss()
+		assertEquals(t, ok, false)
+	})
+
+	t.Run("has nothing to analyze without full history", func(t *testing.T) {
+		g := solverGame(
+			[]string{
+				"x-",
+				"--",
+			},
+			[]string{
+				"--",
+				"xx",
+			},
+		)
+		g.Reveal(0, 0)
+
+		_, ok := g.AnalyzeLoss()
+		assertEquals(t, ok, false)
+	})
+}
+
+// Tries seeds in order until the setup plays a game to a loss, returns that game.
+func seededGameWhere(rules Rules, setup func(g *Game) bool) *Game {
+	for seed := int64(1); ; seed++ {
+		g := NewSeededGame(rules, seed)
+		if setup(g) && g.status == StatusLost && slices.ContainsFunc(g.cells, func(c Cell) bool { return c.isExploded }) {
+			return g
+		}
+	}
+}
@@ -13,28 +13,37 @@ type AutoSaver struct {
 	savePath    string
 	ticker      *time.Ticker
 	needsToSave bool
+	suspended   bool
 	sync.Mutex
 }
 
 // DefaultSavePath returns default auto-save path.
 func DefaultSavePath() string {
+	return DataPath("autosave.json")
+}
+
+// DataPath returns path of a file inside the folder, where all game data is kept.
+func DataPath(name string) string {
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		homeDir = os.TempDir()
 	}
 
-	return path.Join(homeDir, ".hsweeper", "autosave.json")
+	return path.Join(homeDir, ".hsweeper", name)
 }
 
-// NewAutoSaver creates an auto-saver for specified game and save path.
-func NewAutoSaver(game *Game, savePath string) *AutoSaver {
+// NewAutoSaver creates an auto-saver for specified game and save path, saving at most once per interval.
+// Empty save path turns saving off.
+func NewAutoSaver(game *Game, savePath string, interval time.Duration) *AutoSaver {
 	// Make sure we have a folder to save into
-	_ = os.MkdirAll(path.Dir(savePath), 0700)
+	if savePath != "" {
+		_ = os.MkdirAll(path.Dir(savePath), 0700)
+	}
 
 	s := &AutoSaver{
 		game:        game,
 		savePath:    savePath,
-		ticker:      time.NewTicker(5 * time.Second),
+		ticker:      time.NewTicker(interval),
 		needsToSave: true,
 	}
 
@@ -46,7 +55,9 @@ func NewAutoSaver(game *Game, savePath string) *AutoSaver {
 		for {
 			_ = <-s.ticker.C
 			s.Lock()
-			s.save()
+			if !s.suspended {
+				s.save()
+			}
 			s.Unlock()
 		}
 	}()
@@ -61,6 +72,21 @@ func (s *AutoSaver) DeferSave() {
 	s.Unlock()
 }
 
+// Suspend skips saving on ticks until Resume is called, e.g. while the game is paused.
+// Changes deferred meanwhile are saved on the first tick after resuming.
+func (s *AutoSaver) Suspend() {
+	s.Lock()
+	s.suspended = true
+	s.Unlock()
+}
+
+// Resume makes the auto-saver save on ticks again.
+func (s *AutoSaver) Resume() {
+	s.Lock()
+	s.suspended = false
+	s.Unlock()
+}
+
 // Finalize stops the autosaving and forcefully saves one last time.
 func (s *AutoSaver) Finalize() {
 	s.Lock()
@@ -71,7 +97,7 @@ func (s *AutoSaver) Finalize() {
 
 // Persists the game state on disk. Error handling is disabled to not crash the program and keep playing.
 func (s *AutoSaver) save() {
-	if s.needsToSave {
+	if s.needsToSave && s.savePath != "" {
 		if !s.game.IsFinished() {
 			data := s.game.Save().Encode()
 			_ = os.WriteFile(s.savePath, data, 0600)
@@ -7,6 +7,7 @@ type Cell struct {
 	isRevealed    bool
 	isFlagged     bool
 	isQuestioned  bool
+	isExploded    bool
 	adjacentMines int
 }
 
@@ -35,6 +36,11 @@ func (c *Cell) IsQuestioned() bool {
 	return c.isQuestioned
 }
 
+// IsExploded indicates if the cell had a mine, which was revealed and blasted.
+func (c *Cell) IsExploded() bool {
+	return c.isExploded
+}
+
 // AdjacentMines returns precalculated amount of adjacent mines.
 func (c *Cell) AdjacentMines() int {
 	return c.adjacentMines
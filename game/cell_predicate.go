@@ -21,3 +21,7 @@ func isCellFlagged(c *Cell) bool {
 func isCellQuestioned(c *Cell) bool {
 	return c.isQuestioned
 }
+
+func isCellExploded(c *Cell) bool {
+	return c.isExploded
+}
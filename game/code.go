@@ -0,0 +1,296 @@
+package game
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// Codes are case-insensitive, so they survive being read out loud or typed by hand.
+var codeEncoding = base32.NewEncoding("abcdefghijklmnopqrstuvwxyz234567").WithPadding(base32.NoPadding)
+
+const (
+	codeVersion    = 1
+	codeCompressed = 0x80
+
+	codeFlagStarted    = 1
+	codeFlagProgress   = 2
+	codeFlagFirstClick = 4
+	codeFlagHearts     = 8
+)
+
+// ErrInvalidCode is returned when a code can't be decoded.
+var ErrInvalidCode = errors.New("invalid board code")
+
+// EncodeCode converts the game into a short copy-pasteable code.
+// Without progress, the code restarts the same board from the first reveal. Progress is always included for
+// started games of unknown origin (e.g. restored from old saves), as the board can't be reproduced otherwise.
+// Progress keeps everything about the board along with game time, but not the history of moves.
+func (g *Game) EncodeCode(withProgress bool) string {
+	snapshot := g.Save()
+	started := snapshot.Status != StatusReady
+	if started && (snapshot.Seed == 0 || snapshot.StartLocation == 0) {
+		withProgress = true
+	}
+
+	w := &codeWriter{}
+	w.uint(snapshot.Width)
+	w.uint(snapshot.Height)
+	w.uint(snapshot.MinesToPlant)
+	w.uint(snapshot.HeartsToPlant)
+	w.uint(snapshot.rules().Lives)
+	w.string(snapshot.Mode)
+	w.buf = binary.AppendVarint(w.buf, snapshot.Seed)
+
+	flags := byte(0)
+	if snapshot.StartLocation > 0 {
+		flags |= codeFlagStarted
+	}
+	if started && withProgress {
+		flags |= codeFlagProgress
+	}
+	if snapshot.FirstClick != FirstClickOpening {
+		flags |= codeFlagFirstClick
+	}
+	if snapshot.HeartSpawning != HeartSpawningCounter {
+		flags |= codeFlagHearts
+	}
+	w.buf = append(w.buf, flags)
+
+	if flags&codeFlagStarted != 0 {
+		w.uint(snapshot.StartLocation)
+	}
+	if flags&codeFlagFirstClick != 0 {
+		w.uint(int(snapshot.FirstClick))
+	}
+	if flags&codeFlagHearts != 0 {
+		w.uint(int(snapshot.HeartSpawning))
+	}
+
+	if flags&codeFlagProgress != 0 {
+		cellCount := snapshot.Width * snapshot.Height
+		w.buf = append(w.buf, byte(snapshot.Status))
+		w.uint(snapshot.LivesLeft)
+		w.uint(snapshot.HeartsLeft)
+		w.bitmap(cellCount, snapshot.MineLocations)
+		w.bitmap(cellCount, snapshot.RevealedLocations)
+		w.bitmap(cellCount, snapshot.UncollectedHeartLocations)
+		w.bitmap(cellCount, snapshot.FlaggedLocations)
+		w.bitmap(cellCount, snapshot.QuestionedLocations)
+		w.bitmap(cellCount, snapshot.ExplodedLocations)
+		w.uint(snapshot.HeartsCollected)
+		w.buf = binary.AppendVarint(w.buf, snapshot.Elapsed.Milliseconds())
+		if flags&codeFlagHearts != 0 {
+			w.uint(snapshot.CertainMoves)
+			w.bitmap(cellCount, snapshot.HeartLocations)
+			w.uint(snapshot.SafeCells)
+		}
+	}
+
+	// Compress only when it pays off, short codes usually get longer
+	data := append([]byte{codeVersion}, w.buf...)
+	compressed := &bytes.Buffer{}
+	compressed.WriteByte(codeVersion | codeCompressed)
+	fw, _ := flate.NewWriter(compressed, flate.BestCompression)
+	_, _ = fw.Write(w.buf)
+	_ = fw.Close()
+	if compressed.Len() < len(data) {
+		data = compressed.Bytes()
+	}
+
+	return codeEncoding.EncodeToString(data)
+}
+
+// DecodeCode creates a game from a code previously made by EncodeCode.
+// Whitespace is ignored, so codes wrapped over multiple lines can be pasted as is.
+func DecodeCode(code string) (*Game, error) {
+	code = strings.Join(strings.Fields(strings.ToLower(code)), "")
+	data, err := codeEncoding.DecodeString(code)
+	if err != nil || len(data) == 0 {
+		return nil, ErrInvalidCode
+	}
+
+	payload := data[1:]
+	switch data[0] {
+	case codeVersion:
+	case codeVersion | codeCompressed:
+		// A short code may inflate into a huge payload, anything bigger than the biggest board can take is rejected
+		payload, err = io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(payload)), maxCodePayload+1))
+		if err != nil || len(payload) > maxCodePayload {
+			return nil, ErrInvalidCode
+		}
+	default:
+		return nil, ErrInvalidCode
+	}
+
+	r := &codeReader{buf: payload}
+	snapshot := &Snapshot{
+		Width:         r.uint(),
+		Height:        r.uint(),
+		MinesToPlant:  r.uint(),
+		HeartsToPlant: r.uint(),
+		InitialLives:  r.uint(),
+		Mode:          r.string(),
+	}
+	snapshot.LivesLeft = snapshot.InitialLives
+	snapshot.HeartsLeft = snapshot.HeartsToPlant
+	snapshot.Seed = r.varint()
+	flags := r.byte()
+
+	cellCount := snapshot.Width * snapshot.Height
+	if r.err != nil || snapshot.Width < 1 || snapshot.Height < 1 || cellCount > maxCodeCells {
+		return nil, ErrInvalidCode
+	}
+
+	if flags&codeFlagStarted != 0 {
+		snapshot.StartLocation = r.uint()
+		if snapshot.StartLocation < 1 || snapshot.StartLocation > cellCount {
+			return nil, ErrInvalidCode
+		}
+	}
+	if flags&codeFlagFirstClick != 0 {
+		snapshot.FirstClick = FirstClick(r.uint())
+		if snapshot.FirstClick > FirstClickUnprotected {
+			return nil, ErrInvalidCode
+		}
+	}
+	if flags&codeFlagHearts != 0 {
+		snapshot.HeartSpawning = HeartSpawning(r.uint())
+		if snapshot.HeartSpawning > HeartSpawningLogic {
+			return nil, ErrInvalidCode
+		}
+	}
+
+	if flags&codeFlagProgress == 0 {
+		if r.err != nil {
+			return nil, ErrInvalidCode
+		}
+
+		g := NewSeededGame(snapshot.rules(), snapshot.Seed)
+		if snapshot.StartLocation > 0 {
+			start := snapshot.StartLocation - 1
+			g.Reveal(start%snapshot.Width, start/snapshot.Width)
+		}
+		return g, nil
+	}
+
+	snapshot.Status = Status(r.byte())
+	snapshot.LivesLeft = r.uint()
+	snapshot.HeartsLeft = r.uint()
+	snapshot.MineLocations = r.bitmap(cellCount)
+	snapshot.RevealedLocations = r.bitmap(cellCount)
+	snapshot.UncollectedHeartLocations = r.bitmap(cellCount)
+	snapshot.FlaggedLocations = r.bitmap(cellCount)
+	snapshot.QuestionedLocations = r.bitmap(cellCount)
+	snapshot.ExplodedLocations = r.bitmap(cellCount)
+	snapshot.HeartsCollected = r.uint()
+	snapshot.Elapsed = time.Duration(r.varint()) * time.Millisecond
+	if flags&codeFlagHearts != 0 {
+		snapshot.CertainMoves = r.uint()
+		snapshot.HeartLocations = append([]int{}, r.bitmap(cellCount)...)
+		snapshot.SafeCells = r.uint()
+	}
+	if r.err != nil || snapshot.Status > StatusWon || snapshot.Elapsed < 0 {
+		return nil, ErrInvalidCode
+	}
+
+	return RestoreGame(snapshot), nil
+}
+
+// Upper limit on decoded board size, protects from allocating huge boards from malformed codes.
+const maxCodeCells = 1 << 20
+
+// Upper limit on decompressed payload size, all bitmaps of the biggest board take less than a byte per cell.
+const maxCodePayload = maxCodeCells + 1<<10
+
+// Helper to write the binary payload of a code.
+type codeWriter struct {
+	buf []byte
+}
+
+func (w *codeWriter) uint(value int) {
+	w.buf = binary.AppendUvarint(w.buf, uint64(value))
+}
+
+func (w *codeWriter) string(value string) {
+	w.uint(len(value))
+	w.buf = append(w.buf, value...)
+}
+
+// Writes locations as a bitmap of all cells, which is more compact than a list for any noticeable amount.
+func (w *codeWriter) bitmap(cellCount int, locations []int) {
+	bits := make([]byte, (cellCount+7)/8)
+	for _, i := range locations {
+		bits[i/8] |= 1 << (i % 8)
+	}
+	w.buf = append(w.buf, bits...)
+}
+
+// Helper to read the binary payload of a code, remembers the first error to be checked once at the end.
+type codeReader struct {
+	buf []byte
+	err error
+}
+
+func (r *codeReader) uint() int {
+	value, n := binary.Uvarint(r.buf)
+	if n <= 0 || value > maxCodeCells*8 {
+		r.err = ErrInvalidCode
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return int(value)
+}
+
+func (r *codeReader) varint() int64 {
+	value, n := binary.Varint(r.buf)
+	if n <= 0 {
+		r.err = ErrInvalidCode
+		return 0
+	}
+	r.buf = r.buf[n:]
+	return value
+}
+
+func (r *codeReader) string() string {
+	length := r.uint()
+	if len(r.buf) < length {
+		r.err = ErrInvalidCode
+		return ""
+	}
+	value := string(r.buf[:length])
+	r.buf = r.buf[length:]
+	return value
+}
+
+func (r *codeReader) byte() byte {
+	if len(r.buf) < 1 {
+		r.err = ErrInvalidCode
+		return 0
+	}
+	value := r.buf[0]
+	r.buf = r.buf[1:]
+	return value
+}
+
+func (r *codeReader) bitmap(cellCount int) []int {
+	size := (cellCount + 7) / 8
+	if len(r.buf) < size {
+		r.err = ErrInvalidCode
+		return nil
+	}
+
+	var locations []int
+	for i := 0; i < cellCount; i++ {
+		if r.buf[i/8]&(1<<(i%8)) != 0 {
+			locations = append(locations, i)
+		}
+	}
+	r.buf = r.buf[size:]
+	return locations
+}
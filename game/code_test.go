@@ -0,0 +1,163 @@
+package game
+
+import (
+	"bytes"
+	"compress/flate"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGame_EncodeCode_DecodeCode(t *testing.T) {
+	rules := Rules{Mode: "H-Expert", Width: 30, Height: 16, Mines: 99, Hearts: 1, Lives: 2}
+
+	t.Run("restores a ready game", func(t *testing.T) {
+		g := NewSeededGame(rules, 12345)
+
+		decoded, err := DecodeCode(g.EncodeCode(false))
+
+		assertSame(t, err, nil)
+		assertEquals(t, decoded.Status(), StatusReady)
+		assertEquals(t, decoded.Rules(), rules)
+		assertEquals(t, decoded.Seed(), int64(12345))
+	})
+
+	t.Run("restarts a started game from the first reveal", func(t *testing.T) {
+		g := NewSeededGame(rules, 12345)
+		g.Reveal(10, 5)
+		g.ToggleFlag(0, 0)
+
+		decoded, err := DecodeCode(g.EncodeCode(false))
+
+		assertSame(t, err, nil)
+		assertEquals(t, decoded.Status(), StatusStarted)
+		assertEquals(t, decoded.Rules(), rules)
+		assertBitmapEquals(t, decoded.toBitmap(isCellMine), g.toBitmap(isCellMine)...)
+		assertBitmapEquals(t, decoded.toBitmap(isCellRevealed), g.toBitmap(isCellRevealed)...)
+		assertEquals(t, decoded.flaggedCounter, 0)
+		x, y, ok := decoded.StartLocation()
+		assertEquals(t, []any{x, y, ok}, []any{10, 5, true})
+	})
+
+	t.Run("keeps first click policy", func(t *testing.T) {
+		unprotected := rules
+		unprotected.FirstClick = FirstClickUnprotected
+		g := NewSeededGame(unprotected, 12345)
+		g.Reveal(10, 5)
+
+		decoded, err := DecodeCode(g.EncodeCode(false))
+
+		assertSame(t, err, nil)
+		assertEquals(t, decoded.Rules(), unprotected)
+		assertBitmapEquals(t, decoded.toBitmap(isCellMine), g.toBitmap(isCellMine)...)
+	})
+
+	t.Run("keeps heart spawning strategy with progress", func(t *testing.T) {
+		logic := rules
+		logic.HeartSpawning = HeartSpawningLogic
+		g := NewSeededGame(logic, 12345)
+		g.Reveal(10, 5)
+		g.certainMoves = 3
+
+		decoded, err := DecodeCode(g.EncodeCode(true))
+
+		assertSame(t, err, nil)
+		assertEquals(t, decoded.Rules(), logic)
+		assertEquals(t, decoded.certainMoves, 3)
+	})
+
+	t.Run("restores progress", func(t *testing.T) {
+		g := NewSeededGame(rules, 12345)
+		g.Reveal(10, 5)
+		g.ToggleFlag(0, 0)
+		g.ToggleQuestion(1, 0)
+		g.PauseClock()
+
+		decoded, err := DecodeCode(g.EncodeCode(true))
+
+		// History is not a part of the code, time is kept to milliseconds
+		expected := g.Save()
+		expected.Elapsed = expected.Elapsed.Truncate(time.Millisecond)
+		expected.Moves = nil
+		expected.FullHistory = false
+
+		assertSame(t, err, nil)
+		assertEquals(t, decoded.Save(), expected)
+	})
+
+	t.Run("keeps lives used, collected hearts, time and exploded cells with progress", func(t *testing.T) {
+		g := NewSeededGame(rules, 12345)
+		g.Reveal(10, 5)
+		for i := range g.cells {
+			if g.cells[i].isMine {
+				assertEquals(t, g.Reveal(i%30, i/30), RevealResultBlast)
+				break
+			}
+		}
+		g.heartsCollected = 1
+		g.livesLeft++
+		g.PauseClock()
+		g.elapsed = 90*time.Second + 250*time.Millisecond
+
+		decoded, err := DecodeCode(g.EncodeCode(true))
+
+		assertSame(t, err, nil)
+		assertEquals(t, decoded.LivesUsed(), 1)
+		assertEquals(t, decoded.HeartsCollected(), 1)
+		assertEquals(t, decoded.Elapsed(), g.Elapsed())
+		assertBitmapEquals(t, decoded.toBitmap(isCellExploded), g.toBitmap(isCellExploded)...)
+	})
+
+	t.Run("always includes progress for games of unknown origin", func(t *testing.T) {
+		g := RestoreGame(&Snapshot{
+			Status:            StatusStarted,
+			Width:             3,
+			Height:            3,
+			MinesToPlant:      1,
+			LivesLeft:         1,
+			MineLocations:     []int{8},
+			RevealedLocations: []int{0},
+		})
+
+		decoded, err := DecodeCode(g.EncodeCode(false))
+
+		assertSame(t, err, nil)
+		assertEquals(t, decoded.Save(), g.Save())
+	})
+
+	t.Run("ignores case and whitespace", func(t *testing.T) {
+		g := NewSeededGame(rules, 12345)
+		code := g.EncodeCode(false)
+		mangled := strings.ToUpper(code[:5]) + "\n  " + code[5:] + " "
+
+		decoded, err := DecodeCode(mangled)
+
+		assertSame(t, err, nil)
+		assertEquals(t, decoded.Seed(), int64(12345))
+	})
+
+	t.Run("rejects codes, which decompress into too much data", func(t *testing.T) {
+		// Valid payload of a short code padded with zeros, which compress into almost nothing
+		data, _ := codeEncoding.DecodeString(NewSeededGame(rules, 12345).EncodeCode(false))
+		assertEquals(t, data[0], byte(codeVersion))
+		payload := append(data[1:], make([]byte, maxCodePayload)...)
+
+		compressed := &bytes.Buffer{}
+		compressed.WriteByte(codeVersion | codeCompressed)
+		fw, _ := flate.NewWriter(compressed, flate.BestCompression)
+		_, _ = fw.Write(payload)
+		_ = fw.Close()
+
+		_, err := DecodeCode(codeEncoding.EncodeToString(compressed.Bytes()))
+		assertSame(t, err, ErrInvalidCode)
+	})
+
+	t.Run("rejects invalid codes", func(t *testing.T) {
+		code := NewSeededGame(rules, 12345).EncodeCode(false)
+
+		for _, invalid := range []string{"", "!!!", "aaaaaaaa", code[:len(code)-3]} {
+			_, err := DecodeCode(invalid)
+			assertSame(t, err, ErrInvalidCode)
+		}
+	})
+}
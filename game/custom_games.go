@@ -0,0 +1,65 @@
+package game
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"slices"
+)
+
+// CustomGames keeps the last played custom rules and the ones saved as named presets.
+type CustomGames struct {
+	Last    Rules
+	Presets []Rules // Named by their mode
+}
+
+// DefaultCustomGamesPath returns default custom games path.
+func DefaultCustomGamesPath() string {
+	return DataPath("custom.json")
+}
+
+// LoadCustomGames loads custom games from disk, missing or broken file results in no custom games.
+func LoadCustomGames(path string) *CustomGames {
+	customGames := &CustomGames{}
+	if data, err := os.ReadFile(path); err == nil {
+		if json.Unmarshal(data, customGames) != nil {
+			customGames = &CustomGames{}
+		}
+	}
+
+	return customGames
+}
+
+// Save persists custom games on disk.
+func (c *CustomGames) Save(filePath string) error {
+	if err := os.MkdirAll(path.Dir(filePath), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, 0600)
+}
+
+// SavePreset adds a preset, replacing any existing one with the same name.
+func (c *CustomGames) SavePreset(rules Rules) {
+	if i := c.presetIndex(rules.Mode); i >= 0 {
+		c.Presets[i] = rules
+	} else {
+		c.Presets = append(c.Presets, rules)
+	}
+}
+
+// RemovePreset removes a preset by its name, if there's one.
+func (c *CustomGames) RemovePreset(name string) {
+	if i := c.presetIndex(name); i >= 0 {
+		c.Presets = slices.Delete(c.Presets, i, i+1)
+	}
+}
+
+func (c *CustomGames) presetIndex(name string) int {
+	return slices.IndexFunc(c.Presets, func(rules Rules) bool { return rules.Mode == name })
+}
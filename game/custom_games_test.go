@@ -0,0 +1,36 @@
+package game
+
+import (
+	"path"
+	"testing"
+)
+
+func TestCustomGames(t *testing.T) {
+	big := Rules{Mode: "Big", Width: 50, Height: 30, Mines: 300, Hearts: 2, Lives: 2}
+	tiny := Rules{Mode: "Tiny", Width: 5, Height: 5, Mines: 3, Lives: 1, FirstClick: FirstClickSafe}
+
+	t.Run("saves and removes presets by name", func(t *testing.T) {
+		c := LoadCustomGames(path.Join(t.TempDir(), "missing.json"))
+		c.SavePreset(big)
+		c.SavePreset(tiny)
+
+		harderTiny := tiny
+		harderTiny.Mines = 5
+		c.SavePreset(harderTiny)
+		assertEquals(t, c.Presets, []Rules{big, harderTiny})
+
+		c.RemovePreset("Big")
+		c.RemovePreset("Unknown")
+		assertEquals(t, c.Presets, []Rules{harderTiny})
+	})
+
+	t.Run("survives save and load", func(t *testing.T) {
+		customGamesPath := path.Join(t.TempDir(), "custom.json")
+		c := LoadCustomGames(customGamesPath)
+		c.Last = Rules{Width: 20, Height: 20, Mines: 80, Lives: 1}
+		c.SavePreset(tiny)
+
+		assertSame(t, c.Save(customGamesPath), nil)
+		assertEquals(t, LoadCustomGames(customGamesPath), c)
+	})
+}
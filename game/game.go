@@ -2,29 +2,68 @@ package game
 
 import (
 	"math/rand"
+	"slices"
 	"sync"
+	"time"
 )
 
 // Game encapsulates a game of hsweeper with its entire logic.
 type Game struct {
-	status              Status
-	cells               []Cell
-	width               int
-	height              int
-	minesToPlant        int
-	heartsToPlant       int
-	livesLeft           int
-	minesLeft           int
-	heartsLeft          int
-	unrevealedCounter   int
-	flaggedCounter      int
-	heartSpawnCounter   int
-	heartSpawnThreshold int
+	mode              string
+	status            Status
+	cells             []Cell
+	width             int
+	height            int
+	minesToPlant      int
+	heartsToPlant     int
+	livesLeft         int
+	minesLeft         int
+	heartsLeft        int
+	unrevealedCounter int
+	flaggedCounter    int
+	heartSpawnCounter int
+	heartSpawning     HeartSpawning
+	heartSpawner      heartSpawner
+	certainMoves      int       // Reveals of cells proven safe, only counted for HeartSpawningLogic
+	solution          *Solution // Last solution made to count certain moves, nil until there is one
+	initialLives      int
+	seed              int64
+	startLocation     int
+	heartsCollected   int
+	elapsed           time.Duration
+	clockStartedAt    time.Time
+	moves             []Move
+	fullHistory       bool
+	firstClick        FirstClick
+	retry             bool
 	sync.Mutex
 }
 
 // NewGame creates a new game with desired parameters.
 func NewGame(width, height, minesToPlant, heartsToPlant, livesLeft int) *Game {
+	return NewGameWithRules(Rules{
+		Width:  width,
+		Height: height,
+		Mines:  minesToPlant,
+		Hearts: heartsToPlant,
+		Lives:  livesLeft,
+	})
+}
+
+// NewGameWithRules creates a new game with desired rules and a random seed.
+func NewGameWithRules(rules Rules) *Game {
+	return NewSeededGame(rules, newSeed())
+}
+
+// NewSeededGame creates a new game with desired rules.
+// Mine layout is fully determined by the seed and the location of the first reveal.
+func NewSeededGame(rules Rules, seed int64) *Game {
+	width := rules.Width
+	height := rules.Height
+	minesToPlant := rules.Mines
+	heartsToPlant := rules.Hearts
+	livesLeft := rules.Lives
+
 	// Validate and auto-correct parameters
 	status := StatusReady
 	if width < 1 {
@@ -47,6 +86,7 @@ func NewGame(width, height, minesToPlant, heartsToPlant, livesLeft int) *Game {
 	}
 
 	return &Game{
+		mode:              rules.Mode,
 		status:            status,
 		cells:             make([]Cell, width*height),
 		width:             width,
@@ -56,13 +96,28 @@ func NewGame(width, height, minesToPlant, heartsToPlant, livesLeft int) *Game {
 		livesLeft:         livesLeft,
 		heartsLeft:        heartsToPlant,
 		unrevealedCounter: width * height,
+		initialLives:      livesLeft,
+		seed:              seed,
+		startLocation:     -1,
+		fullHistory:       true,
+		firstClick:        rules.FirstClick,
+		heartSpawning:     rules.HeartSpawning,
+		heartSpawner:      newHeartSpawner(rules.HeartSpawning),
 	}
 }
 
 // RestoreGame creates a game and restores it to the state as told by provided snapshot.
 // Prioritizes creating a playable game with consistent state over being 100% faithful to the snapshot parameters.
 func RestoreGame(snapshot *Snapshot) *Game {
-	game := NewGame(snapshot.Width, snapshot.Height, snapshot.MinesToPlant, snapshot.HeartsToPlant, snapshot.LivesLeft)
+	game := NewSeededGame(snapshot.rules(), snapshot.Seed)
+	game.livesLeft = snapshot.LivesLeft
+	if game.livesLeft < 1 {
+		game.livesLeft = 0
+		game.status = StatusLost
+	}
+	game.moves = slices.Clone(snapshot.Moves)
+	game.retry = snapshot.Retry
+	game.fullHistory = snapshot.FullHistory || snapshot.Status == StatusReady
 
 	// Ignore the rest of snapshot parameters if the game wasn't supposed to start yet
 	if snapshot.Status == StatusReady {
@@ -74,8 +129,12 @@ func RestoreGame(snapshot *Snapshot) *Game {
 		game.status = snapshot.Status
 	}
 
-	// Plant mines
+	// Plant mines, hearts are still placed the way they were decided at the start
 	game.plantMines(snapshot.MineLocations)
+	game.heartSpawner.restore(game, snapshot)
+	if snapshot.StartLocation > 0 && snapshot.StartLocation <= len(game.cells) {
+		game.startLocation = snapshot.StartLocation - 1
+	}
 
 	// Reveal cells one by one with consistency checks
 	for _, i := range snapshot.RevealedLocations {
@@ -100,6 +159,23 @@ func RestoreGame(snapshot *Snapshot) *Game {
 		}
 	}
 
+	// Mark exploded cells with consistency checks, the mine that lost the game stays and has to be revealed
+	for _, i := range snapshot.ExplodedLocations {
+		cell := &game.cells[i]
+		if !cell.isRevealed && cell.isMine && game.status == StatusLost {
+			cell.isRevealed = true
+			game.unrevealedCounter--
+		}
+		if cell.isRevealed {
+			cell.isExploded = true
+		}
+	}
+
+	// Restore counters and game time, the clock stays paused until explicitly resumed
+	game.heartsCollected = snapshot.HeartsCollected
+	game.certainMoves = snapshot.CertainMoves
+	game.elapsed = snapshot.Elapsed
+
 	// Put flags with consistency checks
 	for _, i := range snapshot.FlaggedLocations {
 		cell := &game.cells[i]
@@ -125,20 +201,173 @@ func (g *Game) Save() *Snapshot {
 	g.Lock()
 	defer g.Unlock()
 
-	return &Snapshot{
+	snapshot := &Snapshot{
+		Mode:                      g.mode,
 		Status:                    g.status,
 		Width:                     g.width,
 		Height:                    g.height,
 		MinesToPlant:              g.minesToPlant,
 		HeartsToPlant:             g.heartsToPlant,
 		LivesLeft:                 g.livesLeft,
+		InitialLives:              g.initialLives,
 		HeartsLeft:                g.heartsLeft,
+		Seed:                      g.seed,
+		StartLocation:             g.startLocation + 1,
 		MineLocations:             g.collectLocationsForSnapshot(isCellMine),
 		RevealedLocations:         g.collectLocationsForSnapshot(isCellRevealed),
 		UncollectedHeartLocations: g.collectLocationsForSnapshot(isCellHeart),
 		FlaggedLocations:          g.collectLocationsForSnapshot(isCellFlagged),
 		QuestionedLocations:       g.collectLocationsForSnapshot(isCellQuestioned),
+		ExplodedLocations:         g.collectLocationsForSnapshot(isCellExploded),
+		HeartsCollected:           g.heartsCollected,
+		Elapsed:                   g.Elapsed(),
+		Moves:                     slices.Clone(g.moves),
+		FullHistory:               g.fullHistory,
+		FirstClick:                g.firstClick,
+		Retry:                     g.retry,
+		HeartSpawning:             g.heartSpawning,
+		CertainMoves:              g.certainMoves,
+	}
+	g.heartSpawner.save(snapshot)
+	return snapshot
+}
+
+// CanRetry indicates that the layout can be reproduced: the game has started and its origin is known.
+func (g *Game) CanRetry() bool {
+	return g.status != StatusReady && g.seed != 0 && g.startLocation >= 0
+}
+
+// Retry creates a new game on the same mine layout, already started with the same first reveal.
+// Returns false if the layout can't be reproduced.
+func (g *Game) Retry() (*Game, bool) {
+	if !g.CanRetry() {
+		return nil, false
+	}
+
+	retry := NewSeededGame(g.Rules(), g.seed)
+	retry.retry = true
+	retry.Reveal(g.startLocation%g.width, g.startLocation/g.width)
+	return retry, true
+}
+
+// IsRetry indicates that the game replays the layout of a previously played game.
+func (g *Game) IsRetry() bool {
+	return g.retry
+}
+
+// MarkRetry makes the game count as a retry, for cases when the player may already know its layout.
+func (g *Game) MarkRetry() {
+	g.retry = true
+}
+
+// CanUndo indicates that there are moves to take back, and the game can be replayed without them.
+func (g *Game) CanUndo() bool {
+	return g.fullHistory && g.seed != 0 && len(g.moves) > 0
+}
+
+// Undo creates a new game with the last moves taken back, replaying the rest on the same layout. Game time goes on
+// from where it was. The result counts as a retry, since the undone moves have shown what's under the cells.
+// Returns false if there is nothing to undo or the game can't be replayed.
+func (g *Game) Undo(moves int) (*Game, bool) {
+	g.Lock()
+	defer g.Unlock()
+
+	if !g.CanUndo() || moves < 1 {
+		return nil, false
 	}
+
+	kept := g.moves[:max(len(g.moves)-moves, 0)]
+	undo := NewSeededGame(g.Rules(), g.seed)
+	for _, move := range kept {
+		undo.ApplyMove(move)
+	}
+	undo.stopClock()
+	undo.elapsed = g.Elapsed()
+	undo.moves = slices.Clone(kept)
+	undo.retry = true
+	return undo, true
+}
+
+// Rules returns the rules the game was created with.
+func (g *Game) Rules() Rules {
+	return Rules{
+		Mode:          g.mode,
+		Width:         g.width,
+		Height:        g.height,
+		Mines:         g.minesToPlant,
+		Hearts:        g.heartsToPlant,
+		Lives:         g.initialLives,
+		FirstClick:    g.firstClick,
+		HeartSpawning: g.heartSpawning,
+	}
+}
+
+// Seed returns the seed used to generate mine layout.
+func (g *Game) Seed() int64 {
+	return g.seed
+}
+
+// StartLocation returns coordinates of the first reveal, ok is false if the game hasn't started yet or it's unknown.
+func (g *Game) StartLocation() (x, y int, ok bool) {
+	if g.startLocation < 0 {
+		return 0, 0, false
+	}
+
+	return g.startLocation % g.width, g.startLocation / g.width, true
+}
+
+// Elapsed returns game time, which only runs while the game is started and the clock is not paused.
+func (g *Game) Elapsed() time.Duration {
+	if g.clockStartedAt.IsZero() {
+		return g.elapsed
+	}
+
+	return g.elapsed + time.Since(g.clockStartedAt)
+}
+
+// ResumeClock makes game time run again, does nothing unless the game is started.
+func (g *Game) ResumeClock() {
+	g.Lock()
+	defer g.Unlock()
+
+	if g.status == StatusStarted && g.clockStartedAt.IsZero() {
+		g.clockStartedAt = time.Now()
+	}
+}
+
+// PauseClock stops game time until ResumeClock is called.
+func (g *Game) PauseClock() {
+	g.Lock()
+	defer g.Unlock()
+	g.stopClock()
+}
+
+// Moves returns all recorded moves.
+func (g *Game) Moves() []Move {
+	g.Lock()
+	defer g.Unlock()
+	return slices.Clone(g.moves)
+}
+
+// Clicks returns the amount of moves made.
+func (g *Game) Clicks() int {
+	return len(g.moves)
+}
+
+// HasFullHistory indicates that moves were recorded since the very beginning, so they describe the whole game.
+// That's not the case for games restored from old saves or from codes with progress.
+func (g *Game) HasFullHistory() bool {
+	return g.fullHistory
+}
+
+// HeartsCollected returns the amount of hearts picked up.
+func (g *Game) HeartsCollected() int {
+	return g.heartsCollected
+}
+
+// LivesUsed returns the amount of lives lost to blasts.
+func (g *Game) LivesUsed() int {
+	return g.initialLives + g.heartsCollected - g.livesLeft
 }
 
 // Status returns game status
@@ -196,19 +425,19 @@ func (g *Game) ToggleFlag(x, y int) {
 	g.Lock()
 	defer g.Unlock()
 
-	if g.IsFinished() {
+	cell := g.Cell(x, y)
+	if g.IsFinished() || cell.isRevealed {
 		return
 	}
 
-	cell := g.Cell(x, y)
-	if !cell.isRevealed {
-		cell.isFlagged = !cell.isFlagged
-		cell.isQuestioned = false
-		if cell.isFlagged {
-			g.flaggedCounter++
-		} else {
-			g.flaggedCounter--
-		}
+	// Only moves, which change marks, are recorded, so they don't count as clicks otherwise
+	g.recordMove(MoveToggleFlag, x, y)
+	cell.isFlagged = !cell.isFlagged
+	cell.isQuestioned = false
+	if cell.isFlagged {
+		g.flaggedCounter++
+	} else {
+		g.flaggedCounter--
 	}
 }
 
@@ -217,17 +446,16 @@ func (g *Game) ToggleQuestion(x, y int) {
 	g.Lock()
 	defer g.Unlock()
 
-	if g.IsFinished() {
+	cell := g.Cell(x, y)
+	if g.IsFinished() || cell.isRevealed {
 		return
 	}
 
-	cell := g.Cell(x, y)
-	if !cell.isRevealed {
-		cell.isQuestioned = !cell.isQuestioned
-		if cell.isFlagged {
-			cell.isFlagged = false
-			g.flaggedCounter--
-		}
+	g.recordMove(MoveToggleQuestion, x, y)
+	cell.isQuestioned = !cell.isQuestioned
+	if cell.isFlagged {
+		cell.isFlagged = false
+		g.flaggedCounter--
 	}
 }
 
@@ -236,11 +464,12 @@ func (g *Game) ClearFlagAndQuestion(x, y int) {
 	g.Lock()
 	defer g.Unlock()
 
-	if g.IsFinished() {
+	cell := g.Cell(x, y)
+	if g.IsFinished() || !cell.isFlagged && !cell.isQuestioned {
 		return
 	}
 
-	cell := g.Cell(x, y)
+	g.recordMove(MoveClearFlagAndQuestion, x, y)
 	if cell.isFlagged {
 		cell.isFlagged = false
 		g.flaggedCounter--
@@ -253,15 +482,15 @@ func (g *Game) Pickup(x, y int) {
 	g.Lock()
 	defer g.Unlock()
 
-	if g.IsFinished() {
+	cell := g.Cell(x, y)
+	if g.IsFinished() || !cell.isHeart {
 		return
 	}
 
-	cell := g.Cell(x, y)
-	if cell.isHeart {
-		cell.isHeart = false
-		g.livesLeft++
-	}
+	g.recordMove(MovePickup, x, y)
+	cell.isHeart = false
+	g.livesLeft++
+	g.heartsCollected++
 }
 
 // Reveal reveals a cell, advancing the game forward.
@@ -269,6 +498,12 @@ func (g *Game) Pickup(x, y int) {
 func (g *Game) Reveal(x, y int) RevealResult {
 	g.Lock()
 	defer g.Unlock()
+	if !g.isRevealable(x, y) {
+		return RevealResultBlocked
+	}
+
+	g.recordMove(MoveReveal, x, y)
+	g.countCertainMove([]Point{{x, y}})
 	return g.revealInner(x, y)
 }
 
@@ -280,7 +515,7 @@ func (g *Game) AdvancedReveal(x, y int) RevealResult {
 	cell := g.Cell(x, y)
 
 	// Only allow revealed numbered cells
-	if !cell.isRevealed || cell.adjacentMines == 0 {
+	if g.IsFinished() || !cell.isRevealed || cell.adjacentMines == 0 {
 		return RevealResultBlocked
 	}
 
@@ -295,6 +530,14 @@ func (g *Game) AdvancedReveal(x, y int) RevealResult {
 		return RevealResultBlocked
 	}
 
+	// Proceed only if there is anything left to reveal
+	if !slices.ContainsFunc(g.adjacentPoints(x, y), func(point Point) bool { return g.isRevealable(point.x, point.y) }) {
+		return RevealResultBlocked
+	}
+
+	g.recordMove(MoveAdvancedReveal, x, y)
+	g.countCertainMove(g.adjacentPoints(x, y))
+
 	// Result types are ordered Blocked-Revealed-Blast, so treat the maximum as the combined result
 	// If any were revealed - combined result would be at least revealed, if any blasted - blast
 	result := RevealResultBlocked
@@ -326,6 +569,21 @@ func (g *Game) AdvancedReveal(x, y int) RevealResult {
 	return result
 }
 
+// Records a move, unless the game is already finished.
+func (g *Game) recordMove(action MoveAction, x, y int) {
+	if !g.IsFinished() && !g.IsOutOfBounds(x, y) {
+		g.moves = append(g.moves, Move{Action: action, Location: x + y*g.width, Time: g.Elapsed()})
+	}
+}
+
+// Accumulates game time and stops the clock.
+func (g *Game) stopClock() {
+	if !g.clockStartedAt.IsZero() {
+		g.elapsed += time.Since(g.clockStartedAt)
+		g.clockStartedAt = time.Time{}
+	}
+}
+
 // Returns list of adjacent points, includes only in-bound ones.
 func (g *Game) adjacentPoints(x, y int) []Point {
 	points := make([]Point, 0, 8)
@@ -339,23 +597,59 @@ func (g *Game) adjacentPoints(x, y int) []Point {
 	return points
 }
 
-// Inner implementation of Reveal, extracted to avoid locking twice on recursion.
-func (g *Game) revealInner(x, y int) RevealResult {
-	if g.IsFinished() {
-		return RevealResultBlocked
+// Counts a move, which only reveals cells proven safe. Only HeartSpawningLogic needs the count,
+// so the board isn't solved before every move otherwise.
+func (g *Game) countCertainMove(points []Point) {
+	if g.heartSpawning != HeartSpawningLogic || g.status != StatusStarted {
+		return
+	}
+
+	revealing := make([]Point, 0, len(points))
+	for _, point := range points {
+		cell := g.Cell(point.x, point.y)
+		if !cell.isRevealed && !cell.isFlagged && !cell.isQuestioned {
+			revealing = append(revealing, point)
+		}
+	}
+	if len(revealing) == 0 {
+		return
+	}
+
+	// Cells proven safe stay safe as more of the board is revealed, so the last solution is reused until it falls short
+	if !g.solution.isSafe(revealing) {
+		g.solution = g.Solve()
+		if !g.solution.isSafe(revealing) {
+			return
+		}
+	}
+	g.certainMoves++
+}
+
+// Checks if a reveal of the cell would do anything, only unrevealed and unmarked cells of a game in play are revealed.
+func (g *Game) isRevealable(x, y int) bool {
+	if g.IsFinished() || g.IsOutOfBounds(x, y) {
+		return false
 	}
 
 	cell := g.Cell(x, y)
+	return !cell.isRevealed && !cell.isFlagged && !cell.isQuestioned
+}
 
-	// Only allow unrevealed and unmarked cells
-	if cell.isRevealed || cell.isFlagged || cell.isQuestioned {
+// Inner implementation of Reveal, extracted to avoid locking twice on recursion.
+func (g *Game) revealInner(x, y int) RevealResult {
+	if !g.isRevealable(x, y) {
 		return RevealResultBlocked
 	}
 
+	cell := g.Cell(x, y)
+
 	// First reveal triggers game initialization
 	if g.status == StatusReady {
 		g.plantMines(g.randomMineLocations(x, y))
+		g.heartSpawner.prepare(g)
 		g.status = StatusStarted
+		g.startLocation = x + y*g.width
+		g.clockStartedAt = time.Now()
 	}
 
 	// Mark as revealed
@@ -366,6 +660,7 @@ func (g *Game) revealInner(x, y int) RevealResult {
 	// Check if we hit a mine
 	if cell.isMine {
 		result = RevealResultBlast
+		cell.isExploded = true
 		g.livesLeft--
 
 		if g.livesLeft > 0 {
@@ -388,6 +683,7 @@ func (g *Game) revealInner(x, y int) RevealResult {
 		} else {
 			// No lives left, declare loss
 			g.status = StatusLost
+			g.stopClock()
 		}
 	}
 
@@ -399,6 +695,7 @@ func (g *Game) revealInner(x, y int) RevealResult {
 	// We are still alive and only mines are left unrevealed, declare victory
 	if g.status != StatusLost && g.unrevealedCounter == g.minesLeft {
 		g.status = StatusWon
+		g.stopClock()
 	}
 
 	return result
@@ -411,7 +708,7 @@ func (g *Game) propagateReveal(x, y int) {
 
 	// Process heart spawning here, as revealed isolated cells are all candidates for having a pickup
 	g.heartSpawnCounter++
-	if g.heartsLeft > 0 && g.heartSpawnCounter%g.heartSpawnThreshold == 0 {
+	if g.heartsLeft > 0 && g.heartSpawner.spawn(g, x+y*g.width) {
 		cell.isHeart = true
 		g.heartsLeft--
 	}
@@ -437,17 +734,26 @@ func (g *Game) collectLocationsForSnapshot(predicate CellPredicate) []int {
 	return nil
 }
 
-// Generates random mine locations, excluding 3x3 square around provided coordinates.
+// Generates random mine locations, excluding cells protected by the first click policy around provided coordinates.
 func (g *Game) randomMineLocations(aroundX, aroundY int) []int {
+	protected := 1
+	switch g.firstClick {
+	case FirstClickSafe:
+		protected = 0
+	case FirstClickUnprotected:
+		protected = -1
+	}
+
 	locations := make([]int, 0, g.minesToPlant)
-	for _, i := range rand.Perm(len(g.cells)) {
+	random := rand.New(rand.NewSource(g.seed))
+	for _, i := range random.Perm(len(g.cells)) {
 		if len(locations) == g.minesToPlant {
 			break
 		}
 
 		x := i % g.width
 		y := i / g.width
-		if x < aroundX-1 || x > aroundX+1 || y < aroundY-1 || y > aroundY+1 {
+		if x < aroundX-protected || x > aroundX+protected || y < aroundY-protected || y > aroundY+protected {
 			locations = append(locations, i)
 		}
 	}
@@ -466,7 +772,6 @@ func (g *Game) plantMines(mineLocations []int) {
 	}
 
 	// Pre-calculate all adjacent numbers
-	empty := 0
 	for x := 0; x < g.width; x++ {
 		for y := 0; y < g.height; y++ {
 			cell := g.Cell(x, y)
@@ -476,15 +781,15 @@ func (g *Game) plantMines(mineLocations []int) {
 					cell.adjacentMines++
 				}
 			}
-			if cell.adjacentMines == 0 {
-				empty++
-			}
 		}
 	}
+}
 
-	// Calculate heart spawn threshold
-	g.heartSpawnThreshold = empty / (g.heartsLeft + 1)
-	if g.heartSpawnThreshold == 0 {
-		g.heartSpawnThreshold = 1
+// Generates a random non-zero seed, zero is reserved for games of unknown origin.
+func newSeed() int64 {
+	for {
+		if seed := rand.Int63(); seed != 0 {
+			return seed
+		}
 	}
 }
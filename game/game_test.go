@@ -2,6 +2,7 @@ package game
 
 import (
 	"testing"
+	"time"
 )
 
 func TestNewGame(t *testing.T) {
@@ -19,7 +20,7 @@ func TestNewGame(t *testing.T) {
 		assertEquals(t, g.minesLeft, 0)
 		assertEquals(t, g.flaggedCounter, 0)
 		assertEquals(t, g.heartSpawnCounter, 0)
-		assertEquals(t, g.heartSpawnThreshold, 0)
+		assertEquals(t, g.heartSpawner.(*counterHeartSpawner).threshold, 0)
 
 		assertEquals(t, len(g.cells), 72)
 		for _, c := range g.cells {
@@ -119,7 +120,7 @@ func TestRestoreGame_And_Save(t *testing.T) {
 		assertEquals(t, g.unrevealedCounter, 12)
 		assertEquals(t, g.flaggedCounter, 2)
 		assertEquals(t, g.heartSpawnCounter, 4)
-		assertEquals(t, g.heartSpawnThreshold, 2)
+		assertEquals(t, g.heartSpawner.(*counterHeartSpawner).threshold, 2)
 
 		assertBitmapEquals(t, g.toNumbersMap(),
 			"012210",
@@ -743,6 +744,7 @@ func TestGame_Pickup(t *testing.T) {
 		)
 		assertEquals(t, livesAfterPickedOne, 2)
 		assertEquals(t, livesAfterPickedBoth, 3)
+		assertEquals(t, g.HeartsCollected(), 2)
 	})
 
 	t.Run("does nothing on cells without hearts", func(t *testing.T) {
@@ -936,6 +938,19 @@ func TestGame_Reveal(t *testing.T) {
 		assertEquals(t, g.status, StatusLost)
 		assertEquals(t, g.livesLeft, 0)
 		assertEquals(t, g.minesLeft, 19)
+		assertEquals(t, g.LivesUsed(), 2)
+		assertBitmapEquals(t, g.toBitmap(isCellExploded),
+			"----------",
+			"-x------x-",
+			"----------",
+			"----------",
+			"----------",
+			"----------",
+			"----------",
+			"----------",
+			"----------",
+			"----------",
+		)
 		assertBitmapEquals(t, g.toBitmap(isCellMine),
 			"----------",
 			"-------xx-",
@@ -1475,3 +1490,291 @@ func TestGame_AdvancedReveal(t *testing.T) {
 		})
 	}
 }
+
+func TestGame_Moves(t *testing.T) {
+	t.Run("records moves of all kinds", func(t *testing.T) {
+		g := RestoreGame(&Snapshot{
+			Status:                    StatusStarted,
+			Width:                     4,
+			Height:                    3,
+			MinesToPlant:              1,
+			LivesLeft:                 1,
+			Seed:                      1,
+			FullHistory:               true,
+			MineLocations:             []int{3},
+			RevealedLocations:         []int{2, 8},
+			UncollectedHeartLocations: []int{8},
+		})
+
+		g.ToggleQuestion(0, 0)
+		g.ToggleFlag(3, 0)
+		g.AdvancedReveal(2, 0)
+		g.ClearFlagAndQuestion(0, 0)
+		g.Pickup(0, 2)
+		g.Reveal(0, 0)
+		g.Reveal(-1, 0)
+
+		actions := make([]MoveAction, 0)
+		locations := make([]int, 0)
+		for _, move := range g.Moves() {
+			actions = append(actions, move.Action)
+			locations = append(locations, move.Location)
+		}
+		assertEquals(t, actions, []MoveAction{
+			MoveToggleQuestion,
+			MoveToggleFlag,
+			MoveAdvancedReveal,
+			MoveClearFlagAndQuestion,
+			MovePickup,
+			MoveReveal,
+		})
+		assertEquals(t, locations, []int{0, 3, 2, 0, 8, 0})
+		assertEquals(t, g.Clicks(), 6)
+		assertEquals(t, g.Status(), StatusWon)
+		assertEquals(t, g.HasFullHistory(), true)
+	})
+
+	t.Run("doesn't record moves on finished game", func(t *testing.T) {
+		g := NewSeededGame(Rules{Width: 3, Height: 3, Lives: 1}, 1)
+
+		g.Reveal(1, 1)
+		g.ToggleFlag(0, 0)
+
+		assertEquals(t, g.status, StatusWon)
+		assertEquals(t, g.Clicks(), 1)
+	})
+
+	t.Run("doesn't record marking moves, which change nothing", func(t *testing.T) {
+		g := NewSeededGame(Rules{Width: 5, Height: 5, Mines: 3, Lives: 1}, 1)
+		g.Reveal(2, 2)
+		g.ToggleFlag(2, 2)
+		g.ToggleQuestion(2, 2)
+		g.ClearFlagAndQuestion(2, 2)
+
+		assertEquals(t, g.status, StatusStarted)
+		assertEquals(t, g.Clicks(), 1)
+	})
+
+	t.Run("doesn't record reveals and pickups, which change nothing", func(t *testing.T) {
+		g := NewSeededGame(Rules{Width: 5, Height: 5, Mines: 3, Lives: 1}, 1)
+		g.Reveal(2, 2)
+		g.Reveal(2, 2)
+		g.AdvancedReveal(2, 2)
+		g.Pickup(2, 2)
+		for i := range g.cells {
+			if !g.cells[i].isRevealed {
+				g.ToggleQuestion(i%5, i/5)
+				assertEquals(t, g.Reveal(i%5, i/5), RevealResultBlocked)
+				break
+			}
+		}
+
+		assertEquals(t, g.status, StatusStarted)
+		assertEquals(t, g.Clicks(), 2)
+	})
+
+	t.Run("survives save and restore", func(t *testing.T) {
+		g := NewSeededGame(Rules{Width: 5, Height: 5, Mines: 3, Lives: 1}, 1)
+		g.Reveal(2, 2)
+		g.ToggleFlag(0, 0)
+
+		restored := RestoreGame(g.Save())
+
+		assertEquals(t, restored.Moves(), g.Moves())
+		assertEquals(t, restored.HasFullHistory(), true)
+	})
+
+	t.Run("indicates missing history of restored games", func(t *testing.T) {
+		g := RestoreGame(&Snapshot{Status: StatusStarted, Width: 3, Height: 3, LivesLeft: 1})
+		assertEquals(t, g.HasFullHistory(), false)
+	})
+}
+
+func TestGame_Clock(t *testing.T) {
+	t.Run("doesn't run before game starts", func(t *testing.T) {
+		g := NewGame(5, 5, 3, 0, 1)
+		g.ResumeClock()
+		time.Sleep(time.Millisecond)
+
+		assertEquals(t, g.Elapsed(), time.Duration(0))
+	})
+
+	t.Run("runs after the first reveal and stops when paused", func(t *testing.T) {
+		g := NewSeededGame(Rules{Width: 5, Height: 5, Mines: 3, Lives: 1}, 1)
+		g.Reveal(2, 2)
+		time.Sleep(time.Millisecond)
+		g.PauseClock()
+		elapsedOnPause := g.Elapsed()
+		time.Sleep(time.Millisecond)
+
+		assertEquals(t, elapsedOnPause >= time.Millisecond, true)
+		assertEquals(t, g.Elapsed(), elapsedOnPause)
+	})
+
+	t.Run("stops when game is finished", func(t *testing.T) {
+		g := NewSeededGame(Rules{Width: 3, Height: 3, Lives: 1}, 1)
+		g.Reveal(1, 1)
+		elapsedOnFinish := g.Elapsed()
+		time.Sleep(time.Millisecond)
+
+		assertEquals(t, g.Elapsed(), elapsedOnFinish)
+	})
+
+	t.Run("stays paused after restore until resumed", func(t *testing.T) {
+		g := RestoreGame(&Snapshot{Status: StatusStarted, Width: 3, Height: 3, LivesLeft: 1, Elapsed: time.Minute})
+		time.Sleep(time.Millisecond)
+		elapsedAfterRestore := g.Elapsed()
+		g.ResumeClock()
+		time.Sleep(time.Millisecond)
+
+		assertEquals(t, elapsedAfterRestore, time.Minute)
+		assertEquals(t, g.Elapsed() > time.Minute, true)
+	})
+}
+
+func TestGame_FirstClick(t *testing.T) {
+	plantedMines := func(firstClick FirstClick) int {
+		g := NewSeededGame(Rules{Width: 3, Height: 3, Mines: 8, Lives: 1, FirstClick: firstClick}, 1)
+		g.Reveal(1, 1)
+		snapshot := g.Save()
+		return len(snapshot.MineLocations) + len(snapshot.ExplodedLocations)
+	}
+
+	t.Run("keeps the opening around the first reveal free of mines", func(t *testing.T) {
+		assertEquals(t, plantedMines(FirstClickOpening), 0)
+	})
+
+	t.Run("keeps only the first revealed cell free of mines", func(t *testing.T) {
+		assertEquals(t, plantedMines(FirstClickSafe), 8)
+	})
+
+	t.Run("may put a mine under the first reveal", func(t *testing.T) {
+		blasts := 0
+		for seed := int64(1); seed <= 20; seed++ {
+			g := NewSeededGame(Rules{Width: 3, Height: 3, Mines: 8, Lives: 1, FirstClick: FirstClickUnprotected}, seed)
+			if g.Reveal(1, 1) == RevealResultBlast {
+				blasts++
+			}
+		}
+
+		assertEquals(t, blasts > 0, true)
+	})
+}
+
+func TestGame_Retry(t *testing.T) {
+	rules := Rules{Mode: "Tiny", Width: 8, Height: 8, Mines: 10, Hearts: 1, Lives: 1}
+
+	t.Run("restarts the same layout from the same first reveal", func(t *testing.T) {
+		g := NewSeededGame(rules, 42)
+		g.Reveal(3, 5)
+		g.ToggleFlag(0, 0)
+
+		retry, ok := g.Retry()
+		assertEquals(t, ok, true)
+		assertEquals(t, retry.IsRetry(), true)
+		assertEquals(t, retry.Status(), StatusStarted)
+		assertEquals(t, retry.Rules(), rules)
+		assertEquals(t, retry.Seed(), int64(42))
+		assertEquals(t, retry.Moves(), []Move{{Action: MoveReveal, Location: 3 + 5*8}})
+		assertBitmapEquals(t, retry.toBitmap(isCellMine), g.toBitmap(isCellMine)...)
+		assertBitmapEquals(t, retry.toBitmap(isCellRevealed), g.toBitmap(isCellRevealed)...)
+		assertEquals(t, retry.flaggedCounter, 0)
+	})
+
+	t.Run("keeps being a retry after save and restore", func(t *testing.T) {
+		g := NewSeededGame(rules, 42)
+		g.Reveal(3, 5)
+		retry, _ := g.Retry()
+
+		assertEquals(t, RestoreGame(retry.Save()).IsRetry(), true)
+		assertEquals(t, NewGameRecord(retry).Retry, true)
+		assertEquals(t, g.IsRetry(), false)
+	})
+
+	t.Run("can't retry a game, which hasn't started", func(t *testing.T) {
+		_, ok := NewSeededGame(rules, 42).Retry()
+		assertEquals(t, ok, false)
+	})
+
+	t.Run("can't retry a game of unknown origin", func(t *testing.T) {
+		g := NewSeededGame(rules, 0)
+		g.Reveal(3, 5)
+
+		_, ok := g.Retry()
+		assertEquals(t, ok, false)
+	})
+}
+
+func TestGame_Undo(t *testing.T) {
+	rules := Rules{Mode: "Tiny", Width: 8, Height: 8, Mines: 10, Hearts: 1, Lives: 1}
+
+	t.Run("takes back the last moves on the same layout", func(t *testing.T) {
+		g := NewSeededGame(rules, 42)
+		g.Reveal(3, 5)
+		g.ToggleFlag(0, 0)
+		g.ToggleQuestion(1, 0)
+
+		undo, ok := g.Undo(2)
+		assertEquals(t, ok, true)
+		assertEquals(t, undo.IsRetry(), true)
+		assertEquals(t, undo.Status(), StatusStarted)
+		assertEquals(t, undo.Seed(), int64(42))
+		assertEquals(t, len(undo.Moves()), 1)
+		assertBitmapEquals(t, undo.toBitmap(isCellMine), g.toBitmap(isCellMine)...)
+		assertBitmapEquals(t, undo.toBitmap(isCellRevealed), g.toBitmap(isCellRevealed)...)
+		assertEquals(t, undo.flaggedCounter, 0)
+		assertEquals(t, undo.Cell(1, 0).IsQuestioned(), false)
+		assertEquals(t, len(g.Moves()), 3)
+	})
+
+	t.Run("brings a lost game back", func(t *testing.T) {
+		g := NewSeededGame(rules, 42)
+		g.Reveal(3, 5)
+		for i := range g.cells {
+			if g.cells[i].isMine && !g.cells[i].isRevealed {
+				g.Reveal(i%8, i/8)
+				break
+			}
+		}
+		assertEquals(t, g.Status(), StatusLost)
+
+		undo, ok := g.Undo(1)
+		assertEquals(t, ok, true)
+		assertEquals(t, undo.Status(), StatusStarted)
+		assertEquals(t, undo.LivesRemaining(), 1)
+	})
+
+	t.Run("takes back everything when asked for more moves than made", func(t *testing.T) {
+		g := NewSeededGame(rules, 42)
+		g.Reveal(3, 5)
+
+		undo, ok := g.Undo(5)
+		assertEquals(t, ok, true)
+		assertEquals(t, undo.Status(), StatusReady)
+		assertEquals(t, len(undo.Moves()), 0)
+	})
+
+	t.Run("keeps game time", func(t *testing.T) {
+		g := NewSeededGame(rules, 42)
+		g.Reveal(3, 5)
+		g.PauseClock()
+		g.elapsed = time.Minute
+		g.ToggleFlag(0, 0)
+
+		undo, _ := g.Undo(1)
+		assertEquals(t, undo.Elapsed(), time.Minute)
+	})
+
+	t.Run("can't undo without moves", func(t *testing.T) {
+		_, ok := NewSeededGame(rules, 42).Undo(1)
+		assertEquals(t, ok, false)
+	})
+
+	t.Run("can't undo a game of unknown origin", func(t *testing.T) {
+		g := NewSeededGame(rules, 0)
+		g.Reveal(3, 5)
+
+		_, ok := g.Undo(1)
+		assertEquals(t, ok, false)
+	})
+}
@@ -0,0 +1,165 @@
+package game
+
+import (
+	"math/rand"
+	"slices"
+)
+
+type (
+	// Decides where hearts appear. Hearts only ever appear on isolated cells (no adjacent mines) as they are revealed,
+	// a strategy picks which of them get one.
+	heartSpawner interface {
+		// prepare is called once mines are planted in a new game.
+		prepare(g *Game)
+		// save puts whatever prepare decided into a snapshot, so restored games don't have to decide again.
+		save(s *Snapshot)
+		// restore is called instead of prepare once mines of a restored game are planted.
+		restore(g *Game, s *Snapshot)
+		// spawn is called for every isolated cell as it's revealed, while there are hearts left to spawn.
+		spawn(g *Game, location int) bool
+	}
+
+	// Spawns a heart on every n-th isolated reveal, spreading hearts evenly over all isolated cells.
+	counterHeartSpawner struct {
+		threshold int
+	}
+
+	// Spawns hearts on isolated cells chosen at random once mines are planted.
+	randomHeartSpawner struct {
+		locations map[int]bool
+	}
+
+	// Spawns a heart once another share of safe cells is revealed, so hearts come with progress.
+	progressHeartSpawner struct {
+		safe int
+	}
+
+	// Spawns a heart after every few reveals of cells proven safe, so guessing earns nothing.
+	logicHeartSpawner struct{}
+)
+
+// How many reveals of cells proven safe earn a heart with HeartSpawningLogic.
+const certainMovesPerHeart = 20
+
+// Creates the spawner of a strategy, unknown strategies fall back to the counter.
+func newHeartSpawner(strategy HeartSpawning) heartSpawner {
+	switch strategy {
+	case HeartSpawningRandom:
+		return &randomHeartSpawner{}
+	case HeartSpawningProgress:
+		return &progressHeartSpawner{}
+	case HeartSpawningLogic:
+		return &logicHeartSpawner{}
+	default:
+		return &counterHeartSpawner{}
+	}
+}
+
+func (s *counterHeartSpawner) prepare(g *Game) {
+	empty := 0
+	for i := range g.cells {
+		if g.cells[i].adjacentMines == 0 {
+			empty++
+		}
+	}
+
+	s.threshold = empty / (g.heartsLeft + 1)
+	if s.threshold == 0 {
+		s.threshold = 1
+	}
+}
+
+func (s *counterHeartSpawner) save(snapshot *Snapshot) {}
+
+func (s *counterHeartSpawner) restore(g *Game, snapshot *Snapshot) {
+	s.prepare(g)
+}
+
+func (s *counterHeartSpawner) spawn(g *Game, location int) bool {
+	return g.heartSpawnCounter%s.threshold == 0
+}
+
+func (s *randomHeartSpawner) prepare(g *Game) {
+	empty := make([]int, 0)
+	for i := range g.cells {
+		if !g.cells[i].isMine && g.cells[i].adjacentMines == 0 {
+			empty = append(empty, i)
+		}
+	}
+
+	// Separate from the mine layout, but just as reproducible, unless the game is of unknown origin
+	seed := ^g.seed
+	if g.seed == 0 {
+		seed = newSeed()
+	}
+	random := rand.New(rand.NewSource(seed))
+	random.Shuffle(len(empty), func(i, j int) { empty[i], empty[j] = empty[j], empty[i] })
+
+	s.locations = make(map[int]bool)
+	for _, i := range empty[:min(g.heartsToPlant, len(empty))] {
+		s.locations[i] = true
+	}
+}
+
+func (s *randomHeartSpawner) save(snapshot *Snapshot) {
+	// Empty but not nil, so no locations at all are told apart from snapshots made before they were saved
+	snapshot.HeartLocations = make([]int, 0, len(s.locations))
+	for i := range s.locations {
+		snapshot.HeartLocations = append(snapshot.HeartLocations, i)
+	}
+	slices.Sort(snapshot.HeartLocations)
+}
+
+func (s *randomHeartSpawner) restore(g *Game, snapshot *Snapshot) {
+	// Snapshots made before the locations were saved have to pick them again
+	if snapshot.HeartLocations == nil {
+		s.prepare(g)
+		return
+	}
+
+	s.locations = make(map[int]bool)
+	for _, i := range snapshot.HeartLocations {
+		if i >= 0 && i < len(g.cells) {
+			s.locations[i] = true
+		}
+	}
+}
+
+func (s *randomHeartSpawner) spawn(g *Game, location int) bool {
+	return s.locations[location]
+}
+
+func (s *progressHeartSpawner) prepare(g *Game) {
+	s.safe = len(g.cells) - g.minesLeft
+}
+
+func (s *progressHeartSpawner) save(snapshot *Snapshot) {
+	snapshot.SafeCells = s.safe
+}
+
+func (s *progressHeartSpawner) restore(g *Game, snapshot *Snapshot) {
+	// Mines removed by blasts don't make the board any bigger, so the count of a new game is kept
+	if snapshot.SafeCells > 0 {
+		s.safe = snapshot.SafeCells
+	} else {
+		s.prepare(g)
+	}
+}
+
+func (s *progressHeartSpawner) spawn(g *Game, location int) bool {
+	// Hearts are due at equal shares of the board, e.g. at 1/3 and 2/3 with two of them
+	revealed := len(g.cells) - g.unrevealedCounter
+	due := revealed * (g.heartsToPlant + 1) / max(s.safe, 1)
+	return g.heartsToPlant-g.heartsLeft < due
+}
+
+func (s *logicHeartSpawner) prepare(g *Game) {}
+
+func (s *logicHeartSpawner) save(snapshot *Snapshot) {}
+
+func (s *logicHeartSpawner) restore(g *Game, snapshot *Snapshot) {}
+
+func (s *logicHeartSpawner) spawn(g *Game, location int) bool {
+	// Hearts earned while revealing numbers show up on the next isolated cell
+	return g.heartsToPlant-g.heartsLeft < g.certainMoves/certainMovesPerHeart
+}
@@ -0,0 +1,168 @@
+package game
+
+import (
+	"testing"
+)
+
+// Reveals every safe cell of a started game in order, so the game is won.
+func revealAllSafe(g *Game) {
+	for i := range g.cells {
+		if !g.cells[i].isMine {
+			g.Reveal(i%g.width, i/g.width)
+		}
+	}
+}
+
+func TestHeartSpawning(t *testing.T) {
+	rules := Rules{Width: 16, Height: 16, Mines: 30, Hearts: 3, Lives: 1}
+
+	t.Run("spawns all hearts on a cleared board with every strategy but logic", func(t *testing.T) {
+		for _, strategy := range []HeartSpawning{HeartSpawningCounter, HeartSpawningRandom, HeartSpawningProgress} {
+			rules := rules
+			rules.HeartSpawning = strategy
+			g := NewSeededGame(rules, 7)
+			g.Reveal(8, 8)
+			revealAllSafe(g)
+
+			assertEquals(t, g.Status(), StatusWon)
+			assertEquals(t, g.heartsLeft, 0)
+		}
+	})
+
+	t.Run("random strategy hides hearts in the same isolated cells for the same seed", func(t *testing.T) {
+		rules := rules
+		rules.HeartSpawning = HeartSpawningRandom
+		heartLocations := func() []int {
+			g := NewSeededGame(rules, 7)
+			g.Reveal(8, 8)
+			revealAllSafe(g)
+			return g.collectLocationsForSnapshot(isCellHeart)
+		}
+
+		locations := heartLocations()
+		assertEquals(t, len(locations), 3)
+		assertEquals(t, heartLocations(), locations)
+
+		g := NewSeededGame(rules, 7)
+		g.Reveal(8, 8)
+		for _, i := range locations {
+			assertEquals(t, g.cells[i].adjacentMines, 0)
+			assertEquals(t, g.heartSpawner.(*randomHeartSpawner).locations[i], true)
+		}
+	})
+
+	t.Run("progress strategy spawns hearts at equal shares of the board", func(t *testing.T) {
+		rules := rules
+		rules.HeartSpawning = HeartSpawningProgress
+		g := NewSeededGame(rules, 7)
+		g.Reveal(8, 8)
+		spawner := g.heartSpawner.(*progressHeartSpawner)
+		assertEquals(t, spawner.safe, 226)
+
+		// Heart is due at every quarter of 226 safe cells
+		g.heartsLeft = 3
+		g.unrevealedCounter = 256 - 56
+		assertEquals(t, spawner.spawn(g, 0), false)
+		g.unrevealedCounter = 256 - 57
+		assertEquals(t, spawner.spawn(g, 0), true)
+		g.heartsLeft = 2
+		assertEquals(t, spawner.spawn(g, 0), false)
+	})
+
+	// Starts a game, which lost a life to a blast, so its restored mines differ from the original layout
+	blastedGame := func(strategy HeartSpawning) *Game {
+		rules := rules
+		rules.HeartSpawning = strategy
+		rules.Lives = 2
+		g := NewSeededGame(rules, 7)
+		g.Reveal(8, 8)
+		for i := range g.cells {
+			if g.cells[i].isMine {
+				assertEquals(t, g.Reveal(i%16, i/16), RevealResultBlast)
+				break
+			}
+		}
+		return g
+	}
+
+	t.Run("random strategy keeps heart locations of a restored game", func(t *testing.T) {
+		g := blastedGame(HeartSpawningRandom)
+		locations := g.heartSpawner.(*randomHeartSpawner).locations
+
+		snapshot, err := DecodeSnapshot(g.Save().Encode())
+		assertSame(t, err, nil)
+		assertEquals(t, RestoreGame(snapshot).heartSpawner.(*randomHeartSpawner).locations, locations)
+
+		decoded, err := DecodeCode(g.EncodeCode(true))
+		assertSame(t, err, nil)
+		assertEquals(t, decoded.heartSpawner.(*randomHeartSpawner).locations, locations)
+	})
+
+	t.Run("progress strategy keeps safe cells of the original layout in a restored game", func(t *testing.T) {
+		g := blastedGame(HeartSpawningProgress)
+
+		snapshot, err := DecodeSnapshot(g.Save().Encode())
+		assertSame(t, err, nil)
+		assertEquals(t, RestoreGame(snapshot).heartSpawner.(*progressHeartSpawner).safe, 226)
+
+		decoded, err := DecodeCode(g.EncodeCode(true))
+		assertSame(t, err, nil)
+		assertEquals(t, decoded.heartSpawner.(*progressHeartSpawner).safe, 226)
+	})
+
+	t.Run("logic strategy counts only reveals of cells proven safe", func(t *testing.T) {
+		rules := rules
+		rules.HeartSpawning = HeartSpawningLogic
+		g := NewSeededGame(rules, 7)
+		g.Reveal(8, 8)
+		assertEquals(t, g.certainMoves, 0)
+
+		safe := g.Solve().SafeLocations()
+		g.Reveal(safe[0]%16, safe[0]/16)
+		assertEquals(t, g.certainMoves, 1)
+
+		// Reveal a cell, which can't be deduced yet
+		solution := g.Solve()
+		for i := range g.cells {
+			if !g.cells[i].isMine && solution.knowledge[i] == KnowledgeUnknown {
+				g.Reveal(i%16, i/16)
+				break
+			}
+		}
+		assertEquals(t, g.certainMoves, 1)
+	})
+
+	t.Run("logic strategy spawns hearts earned by certain moves", func(t *testing.T) {
+		rules := rules
+		rules.HeartSpawning = HeartSpawningLogic
+		g := NewSeededGame(rules, 7)
+		g.Reveal(8, 8)
+		spawner := g.heartSpawner.(*logicHeartSpawner)
+
+		g.heartsLeft = 3
+		g.certainMoves = certainMovesPerHeart - 1
+		assertEquals(t, spawner.spawn(g, 0), false)
+		g.certainMoves = certainMovesPerHeart
+		assertEquals(t, spawner.spawn(g, 0), true)
+		g.heartsLeft = 2
+		assertEquals(t, spawner.spawn(g, 0), false)
+	})
+
+	t.Run("snapshot keeps the strategy and certain moves", func(t *testing.T) {
+		rules := rules
+		rules.HeartSpawning = HeartSpawningLogic
+		g := NewSeededGame(rules, 7)
+		g.Reveal(8, 8)
+		safe := g.Solve().SafeLocations()
+		g.Reveal(safe[0]%16, safe[0]/16)
+
+		snapshot, err := DecodeSnapshot(g.Save().Encode())
+		assertSame(t, err, nil)
+		restored := RestoreGame(snapshot)
+
+		assertEquals(t, restored.Rules(), rules)
+		assertEquals(t, restored.certainMoves, 1)
+		_, isLogic := restored.heartSpawner.(*logicHeartSpawner)
+		assertEquals(t, isLogic, true)
+	})
+}
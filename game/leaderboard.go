@@ -0,0 +1,133 @@
+package game
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"slices"
+	"time"
+)
+
+// LeaderboardSize is the amount of entries kept per game mode.
+const LeaderboardSize = 10
+
+type (
+	// LeaderboardEntry is a single winning game in a leaderboard.
+	LeaderboardEntry struct {
+		Name             string
+		FinishedAt       time.Time
+		Time             time.Duration
+		ThreeBVPerSecond float64
+		UsedLives        bool
+	}
+
+	// Leaderboard keeps the fastest wins of each game mode.
+	Leaderboard struct {
+		Tables   map[string][]LeaderboardEntry
+		LastName string
+	}
+)
+
+// DefaultLeaderboardPath returns default leaderboard path.
+func DefaultLeaderboardPath() string {
+	return DataPath("leaderboard.json")
+}
+
+// NewLeaderboardEntry creates an entry from a game record.
+func NewLeaderboardEntry(name string, record GameRecord) LeaderboardEntry {
+	return LeaderboardEntry{
+		Name:             name,
+		FinishedAt:       record.FinishedAt,
+		Time:             record.Time,
+		ThreeBVPerSecond: record.ThreeBVPerSecond(),
+		UsedLives:        record.LivesUsed > 0,
+	}
+}
+
+// LoadLeaderboard loads leaderboard from disk, missing or broken file results in empty leaderboard.
+func LoadLeaderboard(path string) *Leaderboard {
+	leaderboard := &Leaderboard{}
+	if data, err := os.ReadFile(path); err == nil {
+		if json.Unmarshal(data, leaderboard) != nil {
+			leaderboard = &Leaderboard{}
+		}
+	}
+
+	if leaderboard.Tables == nil {
+		leaderboard.Tables = make(map[string][]LeaderboardEntry)
+	}
+
+	return leaderboard
+}
+
+// Save persists leaderboard on disk.
+func (l *Leaderboard) Save(filePath string) error {
+	if err := os.MkdirAll(path.Dir(filePath), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, 0600)
+}
+
+// Modes returns names of all modes having at least one entry, in alphabetical order.
+func (l *Leaderboard) Modes() []string {
+	modes := make([]string, 0, len(l.Tables))
+	for mode, table := range l.Tables {
+		if len(table) > 0 {
+			modes = append(modes, mode)
+		}
+	}
+	slices.Sort(modes)
+	return modes
+}
+
+// Table returns entries of a mode from the best to the worst.
+func (l *Leaderboard) Table(mode string) []LeaderboardEntry {
+	return l.Tables[mode]
+}
+
+// Qualifies checks if a record would make it into the leaderboard.
+func (l *Leaderboard) Qualifies(record GameRecord) bool {
+	if record.Result != StatusWon || record.Retry {
+		return false
+	}
+
+	return l.rank(record.Rules.Name(), NewLeaderboardEntry("", record)) < LeaderboardSize
+}
+
+// Add puts a winning record into the leaderboard. Returns zero-based rank of the entry, or -1 if it didn't qualify.
+func (l *Leaderboard) Add(name string, record GameRecord) int {
+	if !l.Qualifies(record) {
+		return -1
+	}
+
+	mode := record.Rules.Name()
+	entry := NewLeaderboardEntry(name, record)
+	rank := l.rank(mode, entry)
+
+	table := slices.Insert(slices.Clone(l.Tables[mode]), rank, entry)
+	if len(table) > LeaderboardSize {
+		table = table[:LeaderboardSize]
+	}
+	l.Tables[mode] = table
+	l.LastName = name
+
+	return rank
+}
+
+// Finds a place for the entry. Clean wins are not comparable to ones using extra lives, so they always go first.
+// Otherwise, faster is better and earlier is better on ties.
+func (l *Leaderboard) rank(mode string, entry LeaderboardEntry) int {
+	for i, other := range l.Tables[mode] {
+		if other.UsedLives && !entry.UsedLives || other.UsedLives == entry.UsedLives && entry.Time < other.Time {
+			return i
+		}
+	}
+
+	return len(l.Tables[mode])
+}
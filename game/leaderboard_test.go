@@ -0,0 +1,81 @@
+package game
+
+import (
+	"path"
+	"testing"
+	"time"
+)
+
+func TestLeaderboard_Add(t *testing.T) {
+	expert := Rules{Mode: "H-Expert", Width: 30, Height: 16, Mines: 99, Hearts: 1, Lives: 1}
+	win := func(seconds int, livesUsed int) GameRecord {
+		return GameRecord{
+			Rules:     expert,
+			Result:    StatusWon,
+			LivesUsed: livesUsed,
+			Metrics:   Metrics{Time: time.Duration(seconds) * time.Second, ThreeBV: 150},
+		}
+	}
+	names := func(l *Leaderboard) []string {
+		result := make([]string, 0)
+		for _, entry := range l.Table("H-Expert") {
+			result = append(result, entry.Name)
+		}
+		return result
+	}
+
+	t.Run("orders by time and puts clean wins first", func(t *testing.T) {
+		l := LoadLeaderboard(path.Join(t.TempDir(), "missing.json"))
+
+		assertEquals(t, l.Add("a", win(100, 0)), 0)
+		assertEquals(t, l.Add("b", win(50, 1)), 1)
+		assertEquals(t, l.Add("c", win(80, 0)), 0)
+		assertEquals(t, l.Add("d", win(60, 2)), 3)
+		assertEquals(t, l.Add("e", win(100, 0)), 2)
+
+		assertEquals(t, names(l), []string{"c", "a", "e", "b", "d"})
+		assertEquals(t, l.Table("H-Expert")[3].UsedLives, true)
+		assertEquals(t, l.Table("H-Expert")[3].ThreeBVPerSecond, 3.0)
+		assertEquals(t, l.LastName, "e")
+	})
+
+	t.Run("keeps limited amount of entries", func(t *testing.T) {
+		l := LoadLeaderboard(path.Join(t.TempDir(), "missing.json"))
+		for i := 0; i < LeaderboardSize; i++ {
+			l.Add("slow", win(100+i, 0))
+		}
+
+		assertEquals(t, l.Qualifies(win(200, 0)), false)
+		assertEquals(t, l.Add("slowest", win(200, 0)), -1)
+		assertEquals(t, l.Add("fast", win(10, 0)), 0)
+		assertEquals(t, len(l.Table("H-Expert")), LeaderboardSize)
+		assertEquals(t, l.Table("H-Expert")[LeaderboardSize-1].Time, 108*time.Second)
+	})
+
+	t.Run("ignores lost games", func(t *testing.T) {
+		l := LoadLeaderboard(path.Join(t.TempDir(), "missing.json"))
+		lost := win(10, 1)
+		lost.Result = StatusLost
+
+		assertEquals(t, l.Qualifies(lost), false)
+		assertEquals(t, l.Add("loser", lost), -1)
+		assertEquals(t, l.Modes(), []string{})
+	})
+
+	t.Run("ignores retries", func(t *testing.T) {
+		l := LoadLeaderboard(path.Join(t.TempDir(), "missing.json"))
+		retry := win(10, 0)
+		retry.Retry = true
+
+		assertEquals(t, l.Qualifies(retry), false)
+	})
+
+	t.Run("survives save and load", func(t *testing.T) {
+		leaderboardPath := path.Join(t.TempDir(), "leaderboard.json")
+		l := LoadLeaderboard(leaderboardPath)
+		l.Add("a", win(100, 0))
+
+		assertSame(t, l.Save(leaderboardPath), nil)
+		assertEquals(t, LoadLeaderboard(leaderboardPath), l)
+	})
+}
@@ -0,0 +1,204 @@
+package game
+
+import "time"
+
+// Metrics are standard Minesweeper measures of board difficulty and player skill.
+type Metrics struct {
+	Time     time.Duration
+	Clicks   int
+	ThreeBV  int // Minimum amount of clicks required to clear the board without flags
+	ZiNi     int // Minimum amount of clicks required to clear the board using flags and chords, greedy estimation
+	Openings int // Amount of connected areas of empty cells
+	Islands  int // Amount of connected areas of numbered cells not bordering any opening
+}
+
+// ThreeBVPerSecond returns 3BV divided by game time in seconds.
+func (m Metrics) ThreeBVPerSecond() float64 {
+	if m.Time <= 0 {
+		return 0
+	}
+
+	return float64(m.ThreeBV) / m.Time.Seconds()
+}
+
+// Efficiency returns 3BV divided by the amount of clicks, also known as IOE.
+func (m Metrics) Efficiency() float64 {
+	if m.Clicks == 0 {
+		return 0
+	}
+
+	return float64(m.ThreeBV) / float64(m.Clicks)
+}
+
+// Metrics measures the game, board metrics are calculated from the original mine layout,
+// so mines exploded along the way still count.
+func (g *Game) Metrics() Metrics {
+	g.Lock()
+	defer g.Unlock()
+
+	metrics := Metrics{
+		Time:   g.Elapsed(),
+		Clicks: len(g.moves),
+	}
+	if g.status == StatusReady {
+		return metrics
+	}
+
+	numbers := g.originalNumbers()
+	visited := make([]bool, len(g.cells))
+
+	// Each opening takes a single click, which also reveals its numbered border
+	for i := range g.cells {
+		if !g.wasMine(i) && numbers[i] == 0 && !visited[i] {
+			metrics.Openings++
+			g.visitArea(i, visited, func(j int) bool { return !g.wasMine(j) }, func(j int) bool { return numbers[j] == 0 })
+		}
+	}
+
+	// Every remaining numbered cell takes its own click, connected ones form islands
+	for i := range g.cells {
+		if !g.wasMine(i) && !visited[i] {
+			metrics.ThreeBV++
+		}
+	}
+	for i := range g.cells {
+		if !g.wasMine(i) && !visited[i] {
+			metrics.Islands++
+			g.visitArea(i, visited, func(j int) bool { return !g.wasMine(j) }, func(j int) bool { return true })
+		}
+	}
+	metrics.ThreeBV += metrics.Openings
+
+	metrics.ZiNi = g.greedyZiNi(numbers)
+	return metrics
+}
+
+// Indicates if the cell had a mine in the original layout.
+func (g *Game) wasMine(i int) bool {
+	return g.cells[i].isMine || g.cells[i].isExploded
+}
+
+// Calculates adjacent mine numbers of the original layout, as blasts alter the actual cell numbers.
+func (g *Game) originalNumbers() []int {
+	numbers := make([]int, len(g.cells))
+	for i := range g.cells {
+		for _, j := range g.adjacentLocations(i) {
+			if g.wasMine(j) {
+				numbers[i]++
+			}
+		}
+	}
+	return numbers
+}
+
+// Returns list of adjacent cell locations, includes only in-bound ones.
+func (g *Game) adjacentLocations(i int) []int {
+	points := g.adjacentPoints(i%g.width, i/g.width)
+	locations := make([]int, len(points))
+	for k, point := range points {
+		locations[k] = point.x + point.y*g.width
+	}
+	return locations
+}
+
+// Marks a connected area as visited. Cells matching include are visited, cells matching expand also spread further.
+func (g *Game) visitArea(start int, visited []bool, include, expand func(int) bool) {
+	stack := []int{start}
+	visited[start] = true
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		for _, j := range g.adjacentLocations(i) {
+			if !visited[j] && include(j) {
+				visited[j] = true
+				if expand(j) {
+					stack = append(stack, j)
+				}
+			}
+		}
+	}
+}
+
+// Estimates ZiNi with the common greedy approach. All openings are clicked first, then the most profitable chord
+// is repeatedly picked, until no chord saves clicks compared to clicking the remaining cells one by one.
+func (g *Game) greedyZiNi(numbers []int) int {
+	revealed := make([]bool, len(g.cells))
+	flagged := make([]bool, len(g.cells))
+	clicks := 0
+
+	for i := range g.cells {
+		if !g.wasMine(i) && numbers[i] == 0 && !revealed[i] {
+			clicks++
+			revealed[i] = true
+			g.visitArea(i, revealed, func(j int) bool { return !g.wasMine(j) }, func(j int) bool { return numbers[j] == 0 })
+		}
+	}
+
+	// Premium is the amount of clicks saved by chording a cell, including the cost of clicking, flagging and chording
+	premium := func(i int) (value, cost int) {
+		if g.wasMine(i) || numbers[i] == 0 {
+			return 0, 0
+		}
+
+		cost = 1
+		if !revealed[i] {
+			cost++
+		}
+		saved := 0
+		for _, j := range g.adjacentLocations(i) {
+			if g.wasMine(j) && !flagged[j] {
+				cost++
+			} else if !g.wasMine(j) && !revealed[j] {
+				saved++
+			}
+		}
+		return saved - cost, cost
+	}
+
+	premiums := make([]int, len(g.cells))
+	for i := range g.cells {
+		premiums[i], _ = premium(i)
+	}
+
+	for {
+		best := -1
+		for i, value := range premiums {
+			if value > 0 && (best < 0 || value > premiums[best]) {
+				best = i
+			}
+		}
+		if best < 0 {
+			break
+		}
+
+		_, cost := premium(best)
+		clicks += cost
+		revealed[best] = true
+		for _, j := range g.adjacentLocations(best) {
+			if g.wasMine(j) {
+				flagged[j] = true
+			} else {
+				revealed[j] = true
+			}
+		}
+
+		// Chording only affects premiums of cells up to 2 steps away
+		for dy := -2; dy <= 2; dy++ {
+			for dx := -2; dx <= 2; dx++ {
+				x := best%g.width + dx
+				y := best/g.width + dy
+				if !g.IsOutOfBounds(x, y) {
+					premiums[x+y*g.width], _ = premium(x + y*g.width)
+				}
+			}
+		}
+	}
+
+	for i := range g.cells {
+		if !g.wasMine(i) && !revealed[i] {
+			clicks++
+		}
+	}
+
+	return clicks
+}
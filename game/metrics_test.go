@@ -0,0 +1,106 @@
+package game
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGame_Metrics(t *testing.T) {
+	snapshot := &Snapshot{
+		Status:       StatusStarted,
+		Width:        6,
+		Height:       5,
+		MinesToPlant: 3,
+		LivesLeft:    2,
+		MineLocations: locationsFromBitmap(
+			"---x--",
+			"---x--",
+			"------",
+			"------",
+			"-----x",
+		),
+		RevealedLocations: []int{0},
+	}
+
+	t.Run("counts openings together with their borders", func(t *testing.T) {
+		g := RestoreGame(snapshot)
+		metrics := g.Metrics()
+
+		// One opening wraps around the left and bottom, another one is in the top right corner
+		assertEquals(t, metrics.ThreeBV, 2)
+		assertEquals(t, metrics.Openings, 2)
+		assertEquals(t, metrics.Islands, 0)
+		assertEquals(t, metrics.ZiNi, 2)
+	})
+
+	t.Run("counts numbers not bordering any opening", func(t *testing.T) {
+		g := RestoreGame(&Snapshot{
+			Status:        StatusStarted,
+			Width:         5,
+			Height:        1,
+			MinesToPlant:  2,
+			LivesLeft:     1,
+			MineLocations: locationsFromBitmap("x-x--"),
+		})
+		metrics := g.Metrics()
+
+		assertEquals(t, metrics.ThreeBV, 2)
+		assertEquals(t, metrics.Openings, 1)
+		assertEquals(t, metrics.Islands, 1)
+		assertEquals(t, metrics.ZiNi, 2)
+	})
+
+	t.Run("estimates chords in ZiNi", func(t *testing.T) {
+		g := RestoreGame(&Snapshot{
+			Status:       StatusStarted,
+			Width:        4,
+			Height:       3,
+			MinesToPlant: 4,
+			LivesLeft:    1,
+			MineLocations: locationsFromBitmap(
+				"x--x",
+				"----",
+				"x--x",
+			),
+		})
+		metrics := g.Metrics()
+
+		// Chording the middle saves 2 clicks, the last cell is clicked separately
+		assertEquals(t, metrics.ThreeBV, 8)
+		assertEquals(t, metrics.Openings, 0)
+		assertEquals(t, metrics.Islands, 1)
+		assertEquals(t, metrics.ZiNi, 5)
+	})
+
+	t.Run("counts exploded mines as a part of the original layout", func(t *testing.T) {
+		g := RestoreGame(snapshot)
+
+		assertEquals(t, g.Reveal(5, 4), RevealResultBlast)
+		assertEquals(t, g.Metrics().ThreeBV, 2)
+	})
+
+	t.Run("counts recorded moves", func(t *testing.T) {
+		g := RestoreGame(snapshot)
+		g.Reveal(5, 0)
+		g.ToggleFlag(3, 0)
+
+		assertEquals(t, g.Metrics().Clicks, 2)
+	})
+
+	t.Run("measures only moves before game starts", func(t *testing.T) {
+		g := NewGame(6, 5, 3, 0, 1)
+		g.ToggleFlag(0, 0)
+
+		assertEquals(t, g.Metrics(), Metrics{Clicks: 1})
+	})
+}
+
+func TestMetrics_ThreeBVPerSecond(t *testing.T) {
+	assertEquals(t, Metrics{Time: 10 * time.Second, ThreeBV: 8}.ThreeBVPerSecond(), 0.8)
+	assertEquals(t, Metrics{ThreeBV: 8}.ThreeBVPerSecond(), 0.0)
+}
+
+func TestMetrics_Efficiency(t *testing.T) {
+	assertEquals(t, Metrics{Clicks: 4, ThreeBV: 8}.Efficiency(), 2.0)
+	assertEquals(t, Metrics{ThreeBV: 8}.Efficiency(), 0.0)
+}
@@ -0,0 +1,21 @@
+package game
+
+import "time"
+
+type MoveAction byte
+
+const (
+	MoveReveal MoveAction = iota
+	MoveAdvancedReveal
+	MoveToggleFlag
+	MoveToggleQuestion
+	MoveClearFlagAndQuestion
+	MovePickup
+)
+
+// Move is a single recorded player action.
+type Move struct {
+	Action   MoveAction
+	Location int
+	Time     time.Duration // Elapsed game time at the moment of the move
+}
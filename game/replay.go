@@ -0,0 +1,96 @@
+package game
+
+import "errors"
+
+// ErrReplayUnavailable is returned for games without full history, which can't be replayed.
+var ErrReplayUnavailable = errors.New("replay is unavailable")
+
+// Replay steps through recorded moves of a game from the very beginning.
+type Replay struct {
+	rules Rules
+	seed  int64
+	moves []Move
+	game  *Game
+	step  int
+}
+
+// NewReplay creates a replay of a game captured in the snapshot, positioned before the first move.
+func NewReplay(snapshot *Snapshot) (*Replay, error) {
+	if !snapshot.FullHistory || snapshot.Seed == 0 {
+		return nil, ErrReplayUnavailable
+	}
+
+	r := &Replay{
+		rules: snapshot.rules(),
+		seed:  snapshot.Seed,
+		moves: snapshot.Moves,
+	}
+	r.Seek(0)
+	return r, nil
+}
+
+// Game returns the replayed game at the current step. It's replaced with a new instance when seeking backwards.
+func (r *Replay) Game() *Game {
+	return r.game
+}
+
+// Step returns amount of moves applied so far.
+func (r *Replay) Step() int {
+	return r.step
+}
+
+// Len returns total amount of moves.
+func (r *Replay) Len() int {
+	return len(r.moves)
+}
+
+// Move returns the move at specified step.
+func (r *Replay) Move(step int) Move {
+	return r.moves[step]
+}
+
+// Next applies the next move, returns false if there are no more moves.
+func (r *Replay) Next() bool {
+	if r.step >= len(r.moves) {
+		return false
+	}
+
+	r.game.ApplyMove(r.moves[r.step])
+	r.step++
+	return true
+}
+
+// Seek moves to specified step, replaying the game from scratch if needed.
+func (r *Replay) Seek(step int) {
+	step = max(0, min(step, len(r.moves)))
+	if r.game == nil || step < r.step {
+		r.game = NewSeededGame(r.rules, r.seed)
+		r.step = 0
+	}
+
+	for r.step < step {
+		r.Next()
+	}
+}
+
+// ApplyMove makes a move as if it was done by the player.
+func (g *Game) ApplyMove(move Move) RevealResult {
+	x := move.Location % g.width
+	y := move.Location / g.width
+	switch move.Action {
+	case MoveReveal:
+		return g.Reveal(x, y)
+	case MoveAdvancedReveal:
+		return g.AdvancedReveal(x, y)
+	case MoveToggleFlag:
+		g.ToggleFlag(x, y)
+	case MoveToggleQuestion:
+		g.ToggleQuestion(x, y)
+	case MoveClearFlagAndQuestion:
+		g.ClearFlagAndQuestion(x, y)
+	case MovePickup:
+		g.Pickup(x, y)
+	}
+
+	return RevealResultBlocked
+}
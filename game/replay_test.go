@@ -0,0 +1,69 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestReplay(t *testing.T) {
+	rules := Rules{Width: 9, Height: 9, Mines: 10, Hearts: 1, Lives: 2}
+	g := NewSeededGame(rules, 42)
+	g.Reveal(4, 4)
+
+	// Mark the first two cells which are still unrevealed
+	marked := make([]int, 0, 2)
+	for i := 0; len(marked) < 2; i++ {
+		if !g.cells[i].isRevealed {
+			marked = append(marked, i)
+		}
+	}
+	g.ToggleFlag(marked[0]%9, marked[0]/9)
+	g.ToggleQuestion(marked[1]%9, marked[1]/9)
+	g.ClearFlagAndQuestion(marked[1]%9, marked[1]/9)
+	for i := 0; i < 81 && !g.IsFinished(); i++ {
+		g.Reveal(i%9, i/9)
+	}
+	final := g.Save()
+
+	t.Run("replays all moves to the same final state", func(t *testing.T) {
+		r, err := NewReplay(final)
+		assertSame(t, err, nil)
+
+		for r.Next() {
+		}
+
+		assertEquals(t, r.Step(), r.Len())
+		assertEquals(t, r.Game().Status(), g.Status())
+		assertBitmapEquals(t, r.Game().toBitmap(isCellRevealed), g.toBitmap(isCellRevealed)...)
+		assertBitmapEquals(t, r.Game().toBitmap(isCellFlagged), g.toBitmap(isCellFlagged)...)
+		assertBitmapEquals(t, r.Game().toBitmap(isCellExploded), g.toBitmap(isCellExploded)...)
+	})
+
+	t.Run("seeks back and forth", func(t *testing.T) {
+		r, _ := NewReplay(final)
+
+		r.Seek(2)
+		assertEquals(t, r.Step(), 2)
+		assertEquals(t, r.Game().cells[marked[0]].isFlagged, true)
+		assertEquals(t, r.Game().cells[marked[1]].isQuestioned, false)
+
+		r.Seek(3)
+		assertEquals(t, r.Game().cells[marked[1]].isQuestioned, true)
+
+		r.Seek(1)
+		assertEquals(t, r.Step(), 1)
+		assertEquals(t, r.Game().cells[marked[0]].isFlagged, false)
+		assertEquals(t, r.Game().Status(), StatusStarted)
+
+		r.Seek(-5)
+		assertEquals(t, r.Step(), 0)
+		assertEquals(t, r.Game().Status(), StatusReady)
+
+		r.Seek(1000)
+		assertEquals(t, r.Step(), r.Len())
+	})
+
+	t.Run("is unavailable without full history", func(t *testing.T) {
+		_, err := NewReplay(&Snapshot{Status: StatusWon, Width: 3, Height: 3, Seed: 1})
+		assertSame(t, err, ErrReplayUnavailable)
+	})
+}
@@ -0,0 +1,110 @@
+package game
+
+import "fmt"
+
+type (
+	// Rules define parameters of a new game.
+	Rules struct {
+		Mode       string // Display name, groups games of the same kind in statistics
+		Width      int
+		Height     int
+		Mines      int
+		Hearts     int
+		Lives      int
+		FirstClick FirstClick
+		// Strategy of placing hearts, zero value is the original counter-based one
+		HeartSpawning HeartSpawning
+	}
+
+	// FirstClick defines how the first reveal is protected from mines.
+	FirstClick int
+
+	// HeartSpawning defines where hearts appear. Hearts are always found on isolated cells as they are revealed.
+	HeartSpawning int
+)
+
+// Names of built-in game modes, used to tell their games apart in statistics and achievements.
+const (
+	ModeExpert        = "H-Expert"
+	ModeBig           = "H-Big"
+	ModeClassicEasy   = "Classic Easy"
+	ModeClassicMedium = "Classic Medium"
+	ModeClassicExpert = "Classic Expert"
+)
+
+const (
+	// FirstClickOpening keeps 3x3 square around the first reveal free of mines, so it always opens an area.
+	FirstClickOpening FirstClick = iota
+	// FirstClickSafe keeps only the first revealed cell free of mines.
+	FirstClickSafe
+	// FirstClickUnprotected doesn't protect the first reveal at all.
+	FirstClickUnprotected
+)
+
+const (
+	// HeartSpawningCounter spawns hearts on every n-th isolated reveal, spread evenly over the board.
+	HeartSpawningCounter HeartSpawning = iota
+	// HeartSpawningRandom hides hearts in isolated cells chosen at random.
+	HeartSpawningRandom
+	// HeartSpawningProgress spawns hearts as equal shares of the board are cleared.
+	HeartSpawningProgress
+	// HeartSpawningLogic spawns hearts only after enough reveals of cells proven safe, guesses don't count.
+	HeartSpawningLogic
+)
+
+// Name returns display name of the rules, custom rules get a descriptive one.
+// Custom names tell apart every rule, so games with different rules are never mixed in statistics and leaderboards.
+// Size and mines are always listed, other rules only when they differ from the usual (e.g. "Custom 30x16 99 5h 3l").
+func (r Rules) Name() string {
+	if r.Mode != "" {
+		return r.Mode
+	}
+
+	name := fmt.Sprintf("Custom %dx%d %d", r.Width, r.Height, r.Mines)
+	if r.Hearts > 0 {
+		name += fmt.Sprintf(" %dh", r.Hearts)
+	}
+	if r.Lives != 1 {
+		name += fmt.Sprintf(" %dl", r.Lives)
+	}
+	switch r.FirstClick {
+	case FirstClickSafe:
+		name += " safe"
+	case FirstClickUnprotected:
+		name += " unsafe"
+	}
+	if r.Hearts > 0 && r.HeartSpawning != HeartSpawningCounter {
+		name += " " + r.HeartSpawning.String()
+	}
+	return name
+}
+
+// String returns display name of the policy.
+func (f FirstClick) String() string {
+	switch f {
+	case FirstClickOpening:
+		return "opening"
+	case FirstClickSafe:
+		return "safe cell"
+	case FirstClickUnprotected:
+		return "unprotected"
+	default:
+		return "unknown"
+	}
+}
+
+// String returns display name of the strategy.
+func (h HeartSpawning) String() string {
+	switch h {
+	case HeartSpawningCounter:
+		return "counter"
+	case HeartSpawningRandom:
+		return "random"
+	case HeartSpawningProgress:
+		return "progress"
+	case HeartSpawningLogic:
+		return "logic"
+	default:
+		return "unknown"
+	}
+}
@@ -2,22 +2,38 @@ package game
 
 import (
 	"encoding/json"
+	"time"
 )
 
 // Snapshot represents a game state, which can be restored and continued from.
 type Snapshot struct {
+	Mode                      string
 	Status                    Status
 	Width                     int
 	Height                    int
 	MinesToPlant              int
 	HeartsToPlant             int
 	LivesLeft                 int
+	InitialLives              int
 	HeartsLeft                int
+	Seed                      int64
+	StartLocation             int // 1-based, 0 means unknown
 	MineLocations             []int
 	RevealedLocations         []int
 	UncollectedHeartLocations []int
 	FlaggedLocations          []int
 	QuestionedLocations       []int
+	ExplodedLocations         []int
+	HeartsCollected           int
+	Elapsed                   time.Duration
+	Moves                     []Move
+	FullHistory               bool
+	FirstClick                FirstClick
+	Retry                     bool
+	HeartSpawning             HeartSpawning
+	CertainMoves              int   // Reveals of cells proven safe, counted for HeartSpawningLogic
+	HeartLocations            []int // Isolated cells picked for hearts by HeartSpawningRandom
+	SafeCells                 int   // Safe cells of the original layout, counted for HeartSpawningProgress
 }
 
 // Encode converts the snapshot into bytes representation.
@@ -35,3 +51,22 @@ func DecodeSnapshot(bytes []byte) (*Snapshot, error) {
 	err := json.Unmarshal(bytes, &snapshot)
 	return snapshot, err
 }
+
+// Returns rules of the snapshot game, falls back to remaining lives for snapshots made before initial lives were saved.
+func (s *Snapshot) rules() Rules {
+	lives := s.InitialLives
+	if lives == 0 {
+		lives = s.LivesLeft
+	}
+
+	return Rules{
+		Mode:          s.Mode,
+		Width:         s.Width,
+		Height:        s.Height,
+		Mines:         s.MinesToPlant,
+		Hearts:        s.HeartsToPlant,
+		Lives:         lives,
+		FirstClick:    s.FirstClick,
+		HeartSpawning: s.HeartSpawning,
+	}
+}
@@ -2,22 +2,32 @@ package game
 
 import (
 	"testing"
+	"time"
 )
 
 func TestSnapshot_EncodeDecode(t *testing.T) {
 	snapshot := &Snapshot{
+		Mode:                      "H-Expert",
 		Status:                    StatusStarted,
 		Width:                     50,
 		Height:                    40,
 		MinesToPlant:              10,
 		HeartsToPlant:             4,
 		LivesLeft:                 3,
+		InitialLives:              2,
 		HeartsLeft:                2,
+		Seed:                      12345,
+		StartLocation:             2,
 		MineLocations:             []int{4, 5, 6, 7},
 		RevealedLocations:         []int{1, 2, 3},
 		UncollectedHeartLocations: []int{8, 9},
 		FlaggedLocations:          []int{4, 5, 6},
 		QuestionedLocations:       []int{7},
+		ExplodedLocations:         []int{3},
+		HeartsCollected:           2,
+		Elapsed:                   5 * time.Second,
+		Moves:                     []Move{{Action: MoveReveal, Location: 1}, {Action: MovePickup, Location: 3, Time: time.Second}},
+		FullHistory:               true,
 	}
 
 	bytes := snapshot.Encode()
@@ -0,0 +1,357 @@
+package game
+
+import "slices"
+
+type (
+	// Knowledge is what can be deduced about a cell from the visible board.
+	Knowledge int
+
+	// Solution holds deductions about every cell of a game, made from what the player can see.
+	Solution struct {
+		width     int
+		knowledge []Knowledge
+	}
+
+	// A revealed number and the undecided cells around it, holding exactly the amount of mines left of the number.
+	constraint struct {
+		locations []int
+		mines     int
+	}
+)
+
+const (
+	// KnowledgeUnknown is a cell, which might be both a mine or safe.
+	KnowledgeUnknown Knowledge = iota
+	// KnowledgeRevealed is a cell already revealed.
+	KnowledgeRevealed
+	// KnowledgeSafe is an unrevealed cell proven to have no mine.
+	KnowledgeSafe
+	// KnowledgeMine is an unrevealed cell proven to have a mine.
+	KnowledgeMine
+)
+
+// Largest group of cells, which is searched through all mine arrangements, bigger groups rely on simple rules only.
+const maxEnumeratedCells = 24
+
+// Solve deduces which unrevealed cells are certainly safe or certainly mines. Only revealed numbers and the amount
+// of mines left are considered, flags are not trusted. Nothing is known before the first reveal.
+func (g *Game) Solve() *Solution {
+	s := &Solution{
+		width:     g.width,
+		knowledge: make([]Knowledge, len(g.cells)),
+	}
+	if g.status == StatusReady {
+		return s
+	}
+
+	for i := range g.cells {
+		if g.cells[i].isRevealed {
+			s.knowledge[i] = KnowledgeRevealed
+		}
+	}
+
+	for {
+		constraints := s.constraints(g)
+		if s.deduceSingle(constraints) || s.deducePairs(constraints) || s.deduceMineCount(g) {
+			continue
+		}
+		if !s.deduceEnumerated(g, constraints) {
+			break
+		}
+	}
+
+	return s
+}
+
+// Knowledge returns what is known about the cell.
+func (s *Solution) Knowledge(x, y int) Knowledge {
+	return s.knowledge[x+y*s.width]
+}
+
+// SafeLocations returns indices of all unrevealed cells proven safe.
+func (s *Solution) SafeLocations() []int {
+	return s.locations(KnowledgeSafe)
+}
+
+// MineLocations returns indices of all unrevealed cells proven to have mines.
+func (s *Solution) MineLocations() []int {
+	return s.locations(KnowledgeMine)
+}
+
+// Checks if all the cells are proven safe, nothing is proven without a solution.
+func (s *Solution) isSafe(points []Point) bool {
+	if s == nil {
+		return false
+	}
+
+	for _, point := range points {
+		if s.Knowledge(point.x, point.y) != KnowledgeSafe {
+			return false
+		}
+	}
+	return true
+}
+
+// Collects indices of cells with specified knowledge.
+func (s *Solution) locations(knowledge Knowledge) []int {
+	locations := make([]int, 0)
+	for i, k := range s.knowledge {
+		if k == knowledge {
+			locations = append(locations, i)
+		}
+	}
+	return locations
+}
+
+// Builds constraints from revealed numbers with undecided cells around. Known mines are taken off the numbers,
+// including the mine revealed by the blast, which lost the game.
+func (s *Solution) constraints(g *Game) []constraint {
+	constraints := make([]constraint, 0)
+	for i := range g.cells {
+		cell := &g.cells[i]
+		if !cell.isRevealed || cell.isMine {
+			continue
+		}
+
+		c := constraint{mines: cell.adjacentMines}
+		for _, point := range g.adjacentPoints(i%g.width, i/g.width) {
+			location := point.x + point.y*g.width
+			switch s.knowledge[location] {
+			case KnowledgeUnknown:
+				c.locations = append(c.locations, location)
+			case KnowledgeMine:
+				c.mines--
+			case KnowledgeRevealed:
+				if g.cells[location].isMine {
+					c.mines--
+				}
+			}
+		}
+		if len(c.locations) > 0 {
+			constraints = append(constraints, c)
+		}
+	}
+	return constraints
+}
+
+// Decides cells around numbers with no mines left, or with as many mines left as there are cells.
+func (s *Solution) deduceSingle(constraints []constraint) bool {
+	changed := false
+	for _, c := range constraints {
+		if c.mines == 0 {
+			changed = s.decide(c.locations, KnowledgeSafe) || changed
+		} else if c.mines == len(c.locations) {
+			changed = s.decide(c.locations, KnowledgeMine) || changed
+		}
+	}
+	return changed
+}
+
+// Compares overlapping numbers: when cells of one are a subset of another's, the rest of the cells hold the difference.
+func (s *Solution) deducePairs(constraints []constraint) bool {
+	byLocation := make(map[int][]int)
+	for i, c := range constraints {
+		for _, location := range c.locations {
+			byLocation[location] = append(byLocation[location], i)
+		}
+	}
+
+	changed := false
+	for i, small := range constraints {
+		for _, j := range byLocation[small.locations[0]] {
+			big := constraints[j]
+			if i == j || len(small.locations) >= len(big.locations) || !isSubset(small.locations, big.locations) {
+				continue
+			}
+
+			rest := slices.DeleteFunc(slices.Clone(big.locations), func(location int) bool {
+				return slices.Contains(small.locations, location)
+			})
+			mines := big.mines - small.mines
+			if mines == 0 {
+				changed = s.decide(rest, KnowledgeSafe) || changed
+			} else if mines == len(rest) {
+				changed = s.decide(rest, KnowledgeMine) || changed
+			}
+		}
+	}
+	return changed
+}
+
+// Decides all undecided cells at once, when either all mines are found or only mines are left.
+func (s *Solution) deduceMineCount(g *Game) bool {
+	undecided := s.locations(KnowledgeUnknown)
+	mines := s.minesLeft(g)
+	if len(undecided) == 0 {
+		return false
+	}
+
+	if mines == 0 {
+		return s.decide(undecided, KnowledgeSafe)
+	} else if mines == len(undecided) {
+		return s.decide(undecided, KnowledgeMine)
+	}
+	return false
+}
+
+// Tries every arrangement of mines in small groups of connected cells next to numbers.
+// Cells being mines in all valid arrangements are mines, the ones being mines in none are safe.
+func (s *Solution) deduceEnumerated(g *Game, constraints []constraint) bool {
+	mines := s.minesLeft(g)
+	changed := false
+	for _, group := range constraintGroups(constraints) {
+		locations := make([]int, 0)
+		for _, c := range group {
+			for _, location := range c.locations {
+				if !slices.Contains(locations, location) {
+					locations = append(locations, location)
+				}
+			}
+		}
+		if len(locations) > maxEnumeratedCells {
+			continue
+		}
+
+		mineCounts := make([]int, len(locations))
+		arrangements := enumerateArrangements(group, locations, mines, mineCounts)
+		if arrangements == 0 {
+			continue
+		}
+
+		for i, location := range locations {
+			if mineCounts[i] == 0 {
+				changed = s.decide([]int{location}, KnowledgeSafe) || changed
+			} else if mineCounts[i] == arrangements {
+				changed = s.decide([]int{location}, KnowledgeMine) || changed
+			}
+		}
+	}
+	return changed
+}
+
+// Counts mines among undecided cells, that is all mines but the proven and revealed ones.
+func (s *Solution) minesLeft(g *Game) int {
+	mines := g.minesLeft - len(s.locations(KnowledgeMine))
+	for i := range g.cells {
+		if g.cells[i].isRevealed && g.cells[i].isMine {
+			mines--
+		}
+	}
+	return mines
+}
+
+// Sets knowledge of undecided cells, returns true if any were undecided.
+func (s *Solution) decide(locations []int, knowledge Knowledge) bool {
+	changed := false
+	for _, location := range locations {
+		if s.knowledge[location] == KnowledgeUnknown {
+			s.knowledge[location] = knowledge
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Splits constraints into groups sharing no cells with each other.
+func constraintGroups(constraints []constraint) [][]constraint {
+	groupOf := make([]int, len(constraints))
+	for i := range groupOf {
+		groupOf[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		if groupOf[i] != i {
+			groupOf[i] = root(groupOf[i])
+		}
+		return groupOf[i]
+	}
+
+	firstConstraint := make(map[int]int)
+	for i, c := range constraints {
+		for _, location := range c.locations {
+			if j, ok := firstConstraint[location]; ok {
+				groupOf[root(i)] = root(j)
+			} else {
+				firstConstraint[location] = i
+			}
+		}
+	}
+
+	groups := make(map[int][]constraint)
+	order := make([]int, 0)
+	for i, c := range constraints {
+		r := root(i)
+		if _, ok := groups[r]; !ok {
+			order = append(order, r)
+		}
+		groups[r] = append(groups[r], c)
+	}
+
+	result := make([][]constraint, 0, len(order))
+	for _, r := range order {
+		result = append(result, groups[r])
+	}
+	return result
+}
+
+// Counts arrangements of mines satisfying all constraints and using no more than maxMines,
+// adding up how many times each cell holds a mine into mineCounts.
+func enumerateArrangements(constraints []constraint, locations []int, maxMines int, mineCounts []int) int {
+	index := make(map[int]int, len(locations))
+	for i, location := range locations {
+		index[location] = i
+	}
+
+	isMine := make([]bool, len(locations))
+	arrangements := 0
+	var place func(next, mines int)
+	place = func(next, mines int) {
+		// Check every constraint still can be satisfied: not too many mines, and enough cells left for the rest
+		for _, c := range constraints {
+			placed, open := 0, 0
+			for _, location := range c.locations {
+				if i := index[location]; i < next {
+					if isMine[i] {
+						placed++
+					}
+				} else {
+					open++
+				}
+			}
+			if placed > c.mines || placed+open < c.mines {
+				return
+			}
+		}
+
+		if next == len(locations) {
+			arrangements++
+			for i, mine := range isMine {
+				if mine {
+					mineCounts[i]++
+				}
+			}
+			return
+		}
+
+		isMine[next] = false
+		place(next+1, mines)
+		if mines < maxMines {
+			isMine[next] = true
+			place(next+1, mines+1)
+			isMine[next] = false
+		}
+	}
+	place(0, 0)
+
+	return arrangements
+}
+
+// Checks if all elements of a are contained in b.
+func isSubset(a, b []int) bool {
+	for _, x := range a {
+		if !slices.Contains(b, x) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,197 @@
+package game
+
+import "testing"
+
+func TestGame_Solve(t *testing.T) {
+	t.Run("knows nothing before the first reveal", func(t *testing.T) {
+		g := NewGame(4, 3, 2, 0, 1)
+		assertBitmapEquals(t, g.Solve().toBitmap(),
+			"????",
+			"????",
+			"????",
+		)
+	})
+
+	t.Run("decides cells around numbers with all or no mines left", func(t *testing.T) {
+		g := solverGame(
+			[]string{
+				"x----",
+				"-----",
+				"-----",
+			},
+			[]string{
+				"--xxx",
+				"xxxxx",
+				"xxxxx",
+			},
+		)
+		assertBitmapEquals(t, g.Solve().toBitmap(),
+			"ms...",
+			".....",
+			".....",
+		)
+	})
+
+	t.Run("decides cells from overlapping numbers", func(t *testing.T) {
+		g := solverGame(
+			[]string{
+				"x-x-",
+				"----",
+				"----",
+			},
+			[]string{
+				"---x",
+				"xxxx",
+				"xxxx",
+			},
+		)
+		assertBitmapEquals(t, g.Solve().toBitmap(),
+			"msm.",
+			"....",
+			"....",
+		)
+	})
+
+	t.Run("decides cells by trying all arrangements", func(t *testing.T) {
+		g := solverGame(
+			[]string{
+				"----",
+				"----",
+				"-xx-",
+				"x---",
+			},
+			[]string{
+				"--xx",
+				"x-x-",
+				"----",
+				"-x-x",
+			},
+		)
+		assertBitmapEquals(t, g.Solve().toBitmap(),
+			"ss..",
+			".s.s",
+			"smms",
+			"m.s.",
+		)
+	})
+
+	t.Run("leaves undecidable cells unknown", func(t *testing.T) {
+		g := solverGame(
+			[]string{
+				"x-",
+				"--",
+			},
+			[]string{
+				"--",
+				"xx",
+			},
+		)
+		assertBitmapEquals(t, g.Solve().toBitmap(),
+			"??",
+			"..",
+		)
+	})
+
+	t.Run("doesn't trust flags", func(t *testing.T) {
+		g := solverGame(
+			[]string{
+				"x-",
+				"--",
+			},
+			[]string{
+				"--",
+				"xx",
+			},
+		)
+		g.ToggleFlag(1, 0)
+		assertBitmapEquals(t, g.Solve().toBitmap(),
+			"??",
+			"..",
+		)
+	})
+
+	t.Run("uses exploded cells as numbers", func(t *testing.T) {
+		g := solverGame(
+			[]string{
+				"x-x",
+				"---",
+			},
+			[]string{
+				"---",
+				"xxx",
+			},
+		)
+		g.livesLeft = 2
+		g.Reveal(0, 0)
+		assertBitmapEquals(t, g.Solve().toBitmap(),
+			"..m",
+			"...",
+		)
+	})
+
+	t.Run("counts the mine, which exploded and lost the game, as a known mine", func(t *testing.T) {
+		g := solverGame(
+			[]string{
+				"x-x",
+				"---",
+			},
+			[]string{
+				"---",
+				"xxx",
+			},
+		)
+		g.Reveal(0, 0)
+		assertEquals(t, g.Status(), StatusLost)
+		assertBitmapEquals(t, g.Solve().toBitmap(),
+			".sm",
+			"...",
+		)
+	})
+}
+
+func TestSolution_Locations(t *testing.T) {
+	g := solverGame(
+		[]string{
+			"x-x-",
+			"----",
+			"----",
+		},
+		[]string{
+			"---x",
+			"xxxx",
+			"xxxx",
+		},
+	)
+	solution := g.Solve()
+	assertEquals(t, solution.SafeLocations(), []int{1})
+	assertEquals(t, solution.MineLocations(), []int{0, 2})
+	assertEquals(t, solution.Knowledge(1, 0), KnowledgeSafe)
+	assertEquals(t, solution.Knowledge(3, 0), KnowledgeRevealed)
+}
+
+// Creates a started game with mines and revealed cells from bitmaps.
+func solverGame(mines, revealed []string) *Game {
+	return RestoreGame(&Snapshot{
+		Status:            StatusStarted,
+		Width:             len(mines[0]),
+		Height:            len(mines),
+		MinesToPlant:      len(locationsFromBitmap(mines...)),
+		LivesLeft:         1,
+		MineLocations:     locationsFromBitmap(mines...),
+		RevealedLocations: locationsFromBitmap(revealed...),
+	})
+}
+
+// Shows knowledge of every cell: '?' unknown, '.' revealed, 's' safe and 'm' mine.
+func (s *Solution) toBitmap() []string {
+	symbols := map[Knowledge]rune{KnowledgeUnknown: '?', KnowledgeRevealed: '.', KnowledgeSafe: 's', KnowledgeMine: 'm'}
+	result := make([]string, len(s.knowledge)/s.width)
+	for y := range result {
+		line := make([]rune, s.width)
+		for x := range line {
+			line[x] = symbols[s.Knowledge(x, y)]
+		}
+		result[y] = string(line)
+	}
+	return result
+}
@@ -0,0 +1,190 @@
+package game
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"time"
+)
+
+type (
+	// GameRecord describes a finished game.
+	GameRecord struct {
+		FinishedAt      time.Time
+		Rules           Rules
+		Result          Status
+		LivesUsed       int
+		HeartsCollected int
+		Metrics
+		Board *Snapshot // Final state of the game, allows to review or replay it
+		Retry bool      // Replayed layout of an earlier game, kept out of summaries, leaderboards and achievements
+	}
+
+	// Stats keeps records of all finished games.
+	Stats struct {
+		Records []GameRecord
+	}
+
+	// ModeSummary aggregates records of a single game mode.
+	ModeSummary struct {
+		Mode                   string
+		Played                 int
+		Won                    int
+		CurrentStreak          int
+		BestStreak             int
+		BestTime               time.Duration
+		AverageTime            time.Duration
+		AverageLivesUsed       float64
+		AverageHeartsCollected float64
+		AverageThreeBV         float64
+		BestThreeBVPerSecond   float64
+		AverageEfficiency      float64
+		Retries                int // Retries of layouts are counted apart from the rest
+		RetriesWon             int
+	}
+)
+
+// How many of the latest records keep their final board, older ones drop it to keep statistics small.
+const maxRecordedBoards = 100
+
+// DefaultStatsPath returns default statistics path.
+func DefaultStatsPath() string {
+	return DataPath("stats.json")
+}
+
+// NewGameRecord describes a finished game.
+func NewGameRecord(g *Game) GameRecord {
+	return GameRecord{
+		FinishedAt:      time.Now(),
+		Rules:           g.Rules(),
+		Result:          g.Status(),
+		LivesUsed:       g.LivesUsed(),
+		HeartsCollected: g.HeartsCollected(),
+		Metrics:         g.Metrics(),
+		Board:           g.Save(),
+		Retry:           g.IsRetry(),
+	}
+}
+
+// LoadStats loads statistics from disk, missing or broken file results in empty statistics.
+func LoadStats(path string) *Stats {
+	stats := &Stats{}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return stats
+	}
+
+	if json.Unmarshal(data, stats) != nil {
+		return &Stats{}
+	}
+
+	return stats
+}
+
+// RecordGame adds a record to statistics on disk. Returns updated statistics, even if saving them failed.
+func RecordGame(path string, record GameRecord) (*Stats, error) {
+	stats := LoadStats(path)
+	stats.Add(record)
+	return stats, stats.Save(path)
+}
+
+// Save persists statistics on disk.
+func (s *Stats) Save(filePath string) error {
+	if err := os.MkdirAll(path.Dir(filePath), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, 0600)
+}
+
+// Add appends a new record. Only the latest records keep their final boards.
+func (s *Stats) Add(record GameRecord) {
+	s.Records = append(s.Records, record)
+	for i := range len(s.Records) - maxRecordedBoards {
+		s.Records[i].Board = nil
+	}
+}
+
+// Modes returns names of all recorded modes in order of their first appearance.
+func (s *Stats) Modes() []string {
+	modes := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, record := range s.Records {
+		mode := record.Rules.Name()
+		if !seen[mode] {
+			seen[mode] = true
+			modes = append(modes, mode)
+		}
+	}
+	return modes
+}
+
+// Summary aggregates all records of the mode. Records are expected to be in chronological order.
+// Retries are only counted by themselves, so knowing the layout doesn't improve the rest of the numbers.
+func (s *Stats) Summary(mode string) ModeSummary {
+	summary := ModeSummary{Mode: mode}
+
+	var totalTime time.Duration
+	totalLivesUsed := 0
+	totalHeartsCollected := 0
+	totalThreeBV := 0
+	totalEfficiency := 0.0
+	for _, record := range s.Records {
+		if record.Rules.Name() != mode {
+			continue
+		}
+
+		if record.Retry {
+			summary.Retries++
+			if record.Result == StatusWon {
+				summary.RetriesWon++
+			}
+			continue
+		}
+
+		summary.Played++
+		totalLivesUsed += record.LivesUsed
+		totalHeartsCollected += record.HeartsCollected
+		totalThreeBV += record.ThreeBV
+
+		if record.Result == StatusWon {
+			summary.Won++
+			summary.CurrentStreak++
+			summary.BestStreak = max(summary.BestStreak, summary.CurrentStreak)
+			totalTime += record.Time
+			totalEfficiency += record.Efficiency()
+			summary.BestThreeBVPerSecond = max(summary.BestThreeBVPerSecond, record.ThreeBVPerSecond())
+			if summary.BestTime == 0 || record.Time < summary.BestTime {
+				summary.BestTime = record.Time
+			}
+		} else {
+			summary.CurrentStreak = 0
+		}
+	}
+
+	if summary.Played > 0 {
+		summary.AverageLivesUsed = float64(totalLivesUsed) / float64(summary.Played)
+		summary.AverageHeartsCollected = float64(totalHeartsCollected) / float64(summary.Played)
+		summary.AverageThreeBV = float64(totalThreeBV) / float64(summary.Played)
+	}
+	if summary.Won > 0 {
+		summary.AverageTime = totalTime / time.Duration(summary.Won)
+		summary.AverageEfficiency = totalEfficiency / float64(summary.Won)
+	}
+
+	return summary
+}
+
+// WinRate returns share of games won, from 0 to 1.
+func (s ModeSummary) WinRate() float64 {
+	if s.Played == 0 {
+		return 0
+	}
+
+	return float64(s.Won) / float64(s.Played)
+}
@@ -0,0 +1,113 @@
+package game
+
+import (
+	"path"
+	"testing"
+	"time"
+)
+
+func TestStats_Summary(t *testing.T) {
+	expert := Rules{Mode: "H-Expert", Width: 30, Height: 16, Mines: 99, Hearts: 1, Lives: 1}
+	custom := Rules{Width: 10, Height: 10, Mines: 10, Lives: 1}
+	stats := &Stats{Records: []GameRecord{
+		{Rules: expert, Result: StatusWon, LivesUsed: 1, HeartsCollected: 1, Metrics: Metrics{Time: 100 * time.Second, Clicks: 300, ThreeBV: 150}},
+		{Rules: expert, Result: StatusWon, Metrics: Metrics{Time: 80 * time.Second, Clicks: 130, ThreeBV: 130}},
+		{Rules: custom, Result: StatusLost, LivesUsed: 1, Metrics: Metrics{Time: 10 * time.Second, Clicks: 10, ThreeBV: 20}},
+		{Rules: expert, Result: StatusLost, LivesUsed: 2, HeartsCollected: 1, Metrics: Metrics{Time: 30 * time.Second, Clicks: 50, ThreeBV: 140}},
+		{Rules: expert, Result: StatusWon, Retry: true, Metrics: Metrics{Time: 20 * time.Second, Clicks: 140, ThreeBV: 140}},
+		{Rules: expert, Result: StatusLost, Retry: true, LivesUsed: 1, Metrics: Metrics{Time: 5 * time.Second, Clicks: 10, ThreeBV: 140}},
+		{Rules: expert, Result: StatusWon, Metrics: Metrics{Time: 120 * time.Second, Clicks: 320, ThreeBV: 160}},
+	}}
+
+	t.Run("lists modes in order of appearance", func(t *testing.T) {
+		assertEquals(t, stats.Modes(), []string{"H-Expert", "Custom 10x10 10"})
+	})
+
+	t.Run("aggregates records of a mode", func(t *testing.T) {
+		assertEquals(t, stats.Summary("H-Expert"), ModeSummary{
+			Mode:                   "H-Expert",
+			Played:                 4,
+			Won:                    3,
+			CurrentStreak:          1,
+			BestStreak:             2,
+			BestTime:               80 * time.Second,
+			AverageTime:            100 * time.Second,
+			AverageLivesUsed:       0.75,
+			AverageHeartsCollected: 0.5,
+			AverageThreeBV:         145,
+			BestThreeBVPerSecond:   1.625,
+			AverageEfficiency:      0.6666666666666666,
+			Retries:                2,
+			RetriesWon:             1,
+		})
+		assertEquals(t, stats.Summary("H-Expert").WinRate(), 0.75)
+	})
+
+	t.Run("keeps custom games with different rules apart", func(t *testing.T) {
+		stats := &Stats{Records: []GameRecord{
+			{Rules: custom, Result: StatusWon},
+			{Rules: Rules{Width: 10, Height: 10, Mines: 10, Hearts: 2, Lives: 1}, Result: StatusWon},
+			{Rules: Rules{Width: 10, Height: 10, Mines: 10, Lives: 3}, Result: StatusWon},
+			{Rules: Rules{Width: 10, Height: 10, Mines: 10, Lives: 1, FirstClick: FirstClickSafe}, Result: StatusWon},
+			{Rules: Rules{Width: 10, Height: 10, Mines: 10, Hearts: 2, Lives: 1, HeartSpawning: HeartSpawningRandom}, Result: StatusWon},
+			{Rules: custom, Result: StatusLost},
+		}}
+
+		assertEquals(t, stats.Modes(), []string{
+			"Custom 10x10 10",
+			"Custom 10x10 10 2h",
+			"Custom 10x10 10 3l",
+			"Custom 10x10 10 safe",
+			"Custom 10x10 10 2h random",
+		})
+		assertEquals(t, stats.Summary("Custom 10x10 10").Played, 2)
+		assertEquals(t, stats.Summary("Custom 10x10 10 2h").Played, 1)
+	})
+
+	t.Run("returns empty summary for unknown mode", func(t *testing.T) {
+		assertEquals(t, stats.Summary("H-Big"), ModeSummary{Mode: "H-Big"})
+		assertEquals(t, stats.Summary("H-Big").WinRate(), 0.0)
+	})
+}
+
+func TestRecordGame(t *testing.T) {
+	statsPath := path.Join(t.TempDir(), "stats.json")
+	g := NewSeededGame(Rules{Mode: "Tiny", Width: 3, Height: 3, Lives: 1}, 1)
+	g.Reveal(1, 1)
+
+	_, err := RecordGame(statsPath, NewGameRecord(g))
+	assertSame(t, err, nil)
+	updated, err := RecordGame(statsPath, NewGameRecord(g))
+	assertSame(t, err, nil)
+
+	stats := LoadStats(statsPath)
+	assertEquals(t, updated, stats)
+	assertEquals(t, len(stats.Records), 2)
+	assertEquals(t, stats.Records[0].Rules, g.Rules())
+	assertEquals(t, stats.Records[0].Result, StatusWon)
+	assertEquals(t, stats.Records[0].ThreeBV, 1)
+	assertEquals(t, stats.Records[0].Clicks, 1)
+	assertEquals(t, stats.Records[0].Board, g.Save())
+}
+
+func TestStats_Add(t *testing.T) {
+	t.Run("keeps boards of the latest records only", func(t *testing.T) {
+		board := NewSeededGame(Rules{Width: 3, Height: 3, Lives: 1}, 1).Save()
+		stats := &Stats{}
+		for range maxRecordedBoards + 2 {
+			stats.Add(GameRecord{Board: board})
+		}
+
+		assertEquals(t, len(stats.Records), maxRecordedBoards+2)
+		assertSame(t, stats.Records[0].Board, (*Snapshot)(nil))
+		assertSame(t, stats.Records[1].Board, (*Snapshot)(nil))
+		assertSame(t, stats.Records[2].Board, board)
+		assertSame(t, stats.Records[maxRecordedBoards+1].Board, board)
+	})
+}
+
+func TestLoadStats(t *testing.T) {
+	t.Run("returns empty stats for missing file", func(t *testing.T) {
+		assertEquals(t, LoadStats(path.Join(t.TempDir(), "missing.json")), &Stats{})
+	})
+}
@@ -3,6 +3,7 @@ module github.com/borogk/hsweeper
 go 1.25
 
 require (
+	github.com/BurntSushi/toml v1.5.0
 	github.com/gdamore/tcell/v2 v2.13.5
 	github.com/google/go-cmp v0.7.0
 )
@@ -0,0 +1,269 @@
+// Package headless plays a game by text commands, so the engine can be scripted or driven by other programs.
+package headless
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/borogk/hsweeper/game"
+)
+
+type (
+	// Format of responses written by a session.
+	Format byte
+
+	// Session plays a single game by commands, one per line. Cell coordinates are 1-based column and row,
+	// the same as in the command line of the game.
+	Session struct {
+		game   *game.Game
+		format Format
+	}
+
+	// Response is written for every command.
+	Response struct {
+		Command string `json:"command"`
+		Result  string `json:"result,omitempty"` // Outcome of a move: revealed, blast, done or blocked
+		Error   string `json:"error,omitempty"`
+		State
+	}
+
+	// State is what the player can see, the board is only included on request.
+	State struct {
+		Status          string   `json:"status"`
+		Width           int      `json:"width"`
+		Height          int      `json:"height"`
+		MinesRemaining  int      `json:"minesRemaining"`
+		LivesRemaining  int      `json:"livesRemaining"`
+		HeartsCollected int      `json:"heartsCollected"`
+		Board           []string `json:"board,omitempty"`
+	}
+)
+
+const (
+	// FormatText writes a line of space separated key=value pairs, followed by board rows if any.
+	FormatText Format = iota
+	// FormatJSON writes a JSON object per line.
+	FormatJSON
+)
+
+// Symbols of cells on the board, the same as in basic display of the game.
+const (
+	symbolUnrevealed = '#'
+	symbolFlag       = 'F'
+	symbolQuestion   = '?'
+	symbolBlank      = '.'
+	symbolHeart      = '+'
+	symbolExploded   = 'X'
+	symbolMine       = '*'
+)
+
+// NewSession creates a session playing the game.
+func NewSession(g *game.Game, format Format) *Session {
+	return &Session{
+		game:   g,
+		format: format,
+	}
+}
+
+// Run executes commands read from r until it ends, writing responses to w. Empty lines and lines starting with #
+// are skipped, so scripted scenarios may have comments.
+func (s *Session) Run(r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if err := s.write(w, s.Execute(line)); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// Execute runs a single command: reveal, flag, chord or pickup followed by column and row, or state.
+func (s *Session) Execute(line string) Response {
+	fields := strings.Fields(line)
+	response := Response{}
+	if len(fields) == 0 {
+		response.Error = "empty command"
+		return response
+	}
+
+	response.Command = fields[0]
+	switch fields[0] {
+	case "state":
+		if len(fields) > 1 {
+			response.Error = "state takes no arguments"
+		}
+		response.State = s.state(true)
+		return response
+	case "reveal", "flag", "chord", "pickup":
+		x, y, err := s.parseLocation(fields[1:])
+		if err != nil {
+			response.Error = err.Error()
+		} else {
+			response.Result = s.move(fields[0], x, y)
+		}
+	default:
+		response.Error = fmt.Sprintf("unknown command %q, use reveal, flag, chord, pickup or state", fields[0])
+	}
+
+	response.State = s.state(false)
+	return response
+}
+
+// Makes a move, returning its outcome.
+func (s *Session) move(command string, x, y int) string {
+	g := s.game
+	cell := g.Cell(x, y)
+	switch command {
+	case "reveal":
+		return formatRevealResult(g.Reveal(x, y))
+	case "chord":
+		return formatRevealResult(g.AdvancedReveal(x, y))
+	case "flag":
+		flagged := cell.IsFlagged()
+		g.ToggleFlag(x, y)
+		if cell.IsFlagged() == flagged {
+			return "blocked"
+		}
+	case "pickup":
+		hearts := g.HeartsCollected()
+		g.Pickup(x, y)
+		if g.HeartsCollected() == hearts {
+			return "blocked"
+		}
+	}
+	return "done"
+}
+
+// Parses 1-based column and row into cell coordinates.
+func (s *Session) parseLocation(fields []string) (x, y int, err error) {
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("expected column and row separated by a space")
+	}
+
+	x, errX := strconv.Atoi(fields[0])
+	y, errY := strconv.Atoi(fields[1])
+	if errX != nil || errY != nil || s.game.IsOutOfBounds(x-1, y-1) {
+		return 0, 0, fmt.Errorf("column must be from 1 to %d, row from 1 to %d", s.game.Width(), s.game.Height())
+	}
+
+	return x - 1, y - 1, nil
+}
+
+// Captures the state of the game, maybe with the board.
+func (s *Session) state(withBoard bool) State {
+	g := s.game
+	state := State{
+		Status:          formatStatus(g.Status()),
+		Width:           g.Width(),
+		Height:          g.Height(),
+		MinesRemaining:  g.MinesRemaining(),
+		LivesRemaining:  g.LivesRemaining(),
+		HeartsCollected: g.HeartsCollected(),
+	}
+	if withBoard {
+		state.Board = FormatBoard(g)
+	}
+	return state
+}
+
+// Writes the response in the format of the session.
+func (s *Session) write(w io.Writer, response Response) error {
+	if s.format == FormatJSON {
+		data, err := json.Marshal(response)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	}
+
+	fields := make([]string, 0, 8)
+	if response.Error != "" {
+		fields = append(fields, "error="+strconv.Quote(response.Error))
+	}
+	if response.Result != "" {
+		fields = append(fields, "result="+response.Result)
+	}
+	state := response.State
+	fields = append(fields,
+		"status="+state.Status,
+		fmt.Sprintf("width=%d", state.Width),
+		fmt.Sprintf("height=%d", state.Height),
+		fmt.Sprintf("mines=%d", state.MinesRemaining),
+		fmt.Sprintf("lives=%d", state.LivesRemaining),
+		fmt.Sprintf("hearts=%d", state.HeartsCollected),
+	)
+
+	lines := append([]string{strings.Join(fields, " ")}, state.Board...)
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// FormatBoard draws the board as text, a row per line. Only what the player can see is shown,
+// except for mines left on the board after a loss.
+func FormatBoard(g *game.Game) []string {
+	rows := make([]string, g.Height())
+	for y := range g.Height() {
+		row := make([]rune, g.Width())
+		for x := range g.Width() {
+			row[x] = cellSymbol(g, g.Cell(x, y))
+		}
+		rows[y] = string(row)
+	}
+	return rows
+}
+
+func cellSymbol(g *game.Game, cell *game.Cell) rune {
+	switch {
+	// Mine, which exploded and took a spare life, is gone and the cell shows what's under it
+	case cell.IsExploded() && cell.IsMine():
+		return symbolExploded
+	case cell.IsRevealed() && cell.IsHeart():
+		return symbolHeart
+	case cell.IsRevealed() && cell.AdjacentMines() > 0:
+		return rune('0' + cell.AdjacentMines())
+	case cell.IsRevealed():
+		return symbolBlank
+	case cell.IsFlagged():
+		return symbolFlag
+	case g.Status() == game.StatusLost && cell.IsMine():
+		return symbolMine
+	case cell.IsQuestioned():
+		return symbolQuestion
+	default:
+		return symbolUnrevealed
+	}
+}
+
+func formatStatus(status game.Status) string {
+	switch status {
+	case game.StatusStarted:
+		return "started"
+	case game.StatusLost:
+		return "lost"
+	case game.StatusWon:
+		return "won"
+	default:
+		return "ready"
+	}
+}
+
+func formatRevealResult(result game.RevealResult) string {
+	switch result {
+	case game.RevealResultRevealed:
+		return "revealed"
+	case game.RevealResultBlast:
+		return "blast"
+	default:
+		return "blocked"
+	}
+}
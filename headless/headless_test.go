@@ -0,0 +1,122 @@
+package headless
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/borogk/hsweeper/game"
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSession_Run(t *testing.T) {
+	rules := game.Rules{Width: 9, Height: 9, Mines: 10, Lives: 1}
+	script := strings.Join([]string{
+		"# comments and empty lines are skipped",
+		"",
+		"reveal 5 5",
+		"flag 1 1",
+		"reveal 1 1",
+		"bogus",
+		"reveal 0 3",
+		"state",
+	}, "\n")
+
+	t.Run("writes plain text", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := NewSession(game.NewSeededGame(rules, 5), FormatText).Run(strings.NewReader(script), out)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{
+			"result=revealed status=started width=9 height=9 mines=10 lives=1 hearts=0",
+			"result=done status=started width=9 height=9 mines=9 lives=1 hearts=0",
+			"result=blocked status=started width=9 height=9 mines=9 lives=1 hearts=0",
+			`error="unknown command \"bogus\", use reveal, flag, chord, pickup or state" status=started width=9 height=9 mines=9 lives=1 hearts=0`,
+			`error="column must be from 1 to 9, row from 1 to 9" status=started width=9 height=9 mines=9 lives=1 hearts=0`,
+			"status=started width=9 height=9 mines=9 lives=1 hearts=0",
+			"F########",
+			"12#######",
+			".1#######",
+			".12221###",
+			".....1###",
+			"..1111###",
+			"..2######",
+			"..2######",
+			"..1######",
+			"",
+		}
+		if diff := cmp.Diff(strings.Split(out.String(), "\n"), expected); diff != "" {
+			t.Errorf("unexpected output\n%s", diff)
+		}
+	})
+
+	t.Run("writes JSON lines", func(t *testing.T) {
+		out := &bytes.Buffer{}
+		err := NewSession(game.NewSeededGame(rules, 5), FormatJSON).Run(strings.NewReader("reveal 5 5\nstate"), out)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("expected 2 lines, got %d", len(lines))
+		}
+		expected := `{"command":"reveal","result":"revealed","status":"started","width":9,"height":9,` +
+			`"minesRemaining":10,"livesRemaining":1,"heartsCollected":0}`
+		if lines[0] != expected {
+			t.Errorf("unexpected line %s", lines[0])
+		}
+		if !strings.Contains(lines[1], `"board":["#########","12#######"`) {
+			t.Errorf("board is missing in %s", lines[1])
+		}
+	})
+}
+
+func TestSession_Run_Blast(t *testing.T) {
+	g := game.NewSeededGame(game.Rules{Width: 9, Height: 9, Mines: 10, Hearts: 2, Lives: 2}, 5)
+	g.Reveal(4, 4)
+	mineX, mineY := -1, -1
+	for i := 0; i < 81 && mineX < 0; i++ {
+		if g.Cell(i%9, i/9).IsMine() {
+			mineX, mineY = i%9, i/9
+		}
+	}
+
+	out := &bytes.Buffer{}
+	script := fmt.Sprintf("reveal %d %d\nstate", mineX+1, mineY+1)
+	if err := NewSession(g, FormatText).Run(strings.NewReader(script), out); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if !strings.HasPrefix(lines[0], "result=blast status=started") || !strings.Contains(lines[0], "lives=1") {
+		t.Errorf("expected a blast with a life left, got %s", lines[0])
+	}
+
+	// Exploded cell shows what's under the mine, so a number or a heart there can still be used
+	cell := g.Cell(mineX, mineY)
+	expected := '.'
+	if cell.IsHeart() {
+		expected = '+'
+	} else if cell.AdjacentMines() > 0 {
+		expected = rune('0' + cell.AdjacentMines())
+	}
+	if symbol := rune(lines[2+mineY][mineX]); !cell.IsExploded() || symbol != expected {
+		t.Errorf("expected %c on the exploded cell, got %c", expected, symbol)
+	}
+}
+
+func TestFormatBoard(t *testing.T) {
+	g := game.NewSeededGame(game.Rules{Width: 9, Height: 9, Mines: 10, Lives: 1}, 5)
+	for i := 0; i < 81 && !g.IsFinished(); i++ {
+		g.Reveal(i%9, i/9)
+	}
+
+	board := strings.Join(FormatBoard(g), "")
+	if g.Status() != game.StatusLost || !strings.Contains(board, "X") || !strings.Contains(board, "*") {
+		t.Errorf("expected a lost game with the exploded and the remaining mines, got %v", FormatBoard(g))
+	}
+}
@@ -1,10 +1,300 @@
 package main
 
 import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"github.com/borogk/hsweeper/game"
 	"github.com/borogk/hsweeper/ui"
 )
 
+// Set at build time with -ldflags "-X main.version=...", otherwise taken from the module version if there is one.
+var version = "dev"
+
+// A subcommand is a verb given as the first argument, followed by its own flags.
+type subcommand struct {
+	name        string
+	usage       string
+	description string
+	run         func(args []string) error
+}
+
+// Returned when the command line is wrong and usage was already printed.
+var errUsage = errors.New("usage")
+
 func main() {
-	u := ui.NewUiWithTitleMenu()
+	err := run(os.Args[1:])
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// Runs the subcommand given as the first argument, playing is the default when there is none.
+func run(args []string) error {
+	commands := subcommands()
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "-help" || args[0] == "--help") {
+			printUsage(commands)
+			return flag.ErrHelp
+		}
+		return runPlay(args)
+	}
+
+	for _, command := range commands {
+		if command.name == args[0] {
+			return command.run(args[1:])
+		}
+	}
+
+	if args[0] == "help" {
+		printUsage(commands)
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "unknown command %q\n\n", args[0])
+	printUsage(commands)
+	return errUsage
+}
+
+func subcommands() []subcommand {
+	return []subcommand{
+		{"play", "[flags]", "Play the game, the default command", runPlay},
+		{"stats", "", "Print statistics of finished games", runStats},
+		{"replay", "[flags] [FILE]", "Replay a saved game, the last finished one by default", runReplay},
+		{"solve", "CODE", "Print what can be deduced about a board shared as a code", runSolve},
+		{"generate", "[flags]", "Print a board code for the mode, maybe with a seed", runGenerate},
+		{"headless", "[flags]", "Play by commands read from standard input, without the game screen", runHeadless},
+		{"simulate", "[flags]", "Let the bot play many seeded games and report how it went", runSimulate},
+		{"version", "", "Print the version", runVersion},
+	}
+}
+
+func printUsage(commands []subcommand) {
+	fmt.Fprintln(os.Stderr, "Usage: hsweeper [COMMAND] [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, command := range commands {
+		fmt.Fprintf(os.Stderr, "  %-9s %-15s %s\n", command.name, command.usage, command.description)
+	}
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Run 'hsweeper COMMAND -help' to list flags of the command.")
+}
+
+// Creates flags of a subcommand, which print its usage on errors.
+func newFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: hsweeper %s %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// Parses flags of a subcommand, which takes up to maxArgs more arguments. Errors are printed along with usage.
+func parseFlags(fs *flag.FlagSet, args []string, maxArgs int) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() > maxArgs {
+		return usageError(fs, fmt.Sprintf("unexpected argument %q", fs.Arg(maxArgs)))
+	}
+	return nil
+}
+
+// Prints the problem with arguments of a subcommand along with its usage.
+func usageError(fs *flag.FlagSet, problem string) error {
+	fmt.Fprintln(fs.Output(), problem)
+	fs.Usage()
+	return errUsage
+}
+
+// Flags, which tune the user interface of both playing and replays.
+type uiFlags struct {
+	display string
+	theme   string
+	keys    string
+}
+
+func (f *uiFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.display, "display", "auto", "graphics: full (Unicode, 256 colors), basic (ASCII, 16 colors) or auto")
+	fs.StringVar(&f.theme, "theme", "", "color theme to use instead of the configured one")
+	fs.StringVar(&f.keys, "keys", "", "key bindings preset, or a key bindings config file")
+}
+
+func (f *uiFlags) options() (ui.Options, error) {
+	display, err := ui.ParseDisplayMode(f.display)
+	if err != nil {
+		return ui.Options{}, err
+	}
+
+	return ui.Options{Display: display, Theme: f.theme, Keys: f.keys}, nil
+}
+
+// Flags, which pick the rules of a game: a mode, maybe with some of its numbers changed.
+type rulesFlags struct {
+	mode                                string
+	width, height, mines, hearts, lives int
+	heartSpawning                       string
+	seed                                int64
+}
+
+func (f *rulesFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.mode, "mode", "", "built-in mode (expert, big, easy, medium, classic-expert) or a custom preset name")
+	fs.IntVar(&f.width, "width", 0, "board width")
+	fs.IntVar(&f.height, "height", 0, "board height")
+	fs.IntVar(&f.mines, "mines", 0, "amount of mines")
+	fs.IntVar(&f.hearts, "hearts", 0, "amount of hearts")
+	fs.IntVar(&f.lives, "lives", 1, "amount of lives at the start")
+	fs.StringVar(&f.heartSpawning, "heart-spawning", "counter", "where hearts appear: counter, random, progress or logic")
+	fs.Int64Var(&f.seed, "seed", 0, "seed of the mine layout, random by default")
+}
+
+// Builds the rules, nil if neither a mode nor any numbers were given. Changing numbers or heart spawning of a mode
+// makes it a custom game, so it isn't mixed up with the mode in statistics, except for the size of H-Big.
+// Without a mode, the size and mines are required.
+func (f *rulesFlags) rules(fs *flag.FlagSet) (*game.Rules, error) {
+	set := map[string]bool{}
+	fs.Visit(func(fl *flag.Flag) { set[fl.Name] = true })
+	changed := set["width"] || set["height"] || set["mines"] || set["hearts"] || set["lives"]
+	if f.mode == "" && !changed && !set["heart-spawning"] {
+		return nil, nil
+	}
+
+	heartSpawning, err := parseHeartSpawning(f.heartSpawning)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules game.Rules
+	if f.mode != "" {
+		if rules, err = ui.FindMode(f.mode); err != nil {
+			return nil, err
+		}
+		if rules.Width == 0 && (changed || set["heart-spawning"]) {
+			// Sized by the screen, but any size can be given to have the rest of the numbers follow it
+			if !set["width"] || !set["height"] || set["mines"] || set["hearts"] || set["lives"] {
+				return nil, fmt.Errorf("%s is sized by the screen, only -width and -height can be given together", rules.Mode)
+			}
+			rules = ui.BigRules(f.width, f.height)
+			changed = false
+		}
+	} else if !set["width"] || !set["height"] || !set["mines"] {
+		return nil, errors.New("a custom game needs -width, -height and -mines, or pick a -mode")
+	} else {
+		rules.Lives = f.lives
+	}
+
+	if changed {
+		rules.Mode = ""
+	}
+	if set["width"] {
+		rules.Width = f.width
+	}
+	if set["height"] {
+		rules.Height = f.height
+	}
+	if set["mines"] {
+		rules.Mines = f.mines
+	}
+	if set["hearts"] {
+		rules.Hearts = f.hearts
+	}
+	if set["lives"] {
+		rules.Lives = f.lives
+	}
+	if heartSpawning != rules.HeartSpawning {
+		rules.HeartSpawning = heartSpawning
+		rules.Mode = ""
+	}
+
+	return &rules, nil
+}
+
+// Converts heart spawning strategy name, as given in the command line.
+func parseHeartSpawning(name string) (game.HeartSpawning, error) {
+	for strategy := game.HeartSpawningCounter; strategy <= game.HeartSpawningLogic; strategy++ {
+		if strategy.String() == name {
+			return strategy, nil
+		}
+	}
+	return game.HeartSpawningCounter, fmt.Errorf("unknown heart spawning %q, expected counter, random, progress or logic", name)
+}
+
+func runPlay(args []string) error {
+	fs := newFlagSet("play", "[flags]")
+	var uiFlags uiFlags
+	var rulesFlags rulesFlags
+	uiFlags.register(fs)
+	rulesFlags.register(fs)
+	code := fs.String("code", "", "play the board from a shareable code")
+	savePath := fs.String("save-path", "", "file to keep the game in progress in, instead of the default one")
+	noSave := fs.Bool("no-save", false, "don't keep the game in progress")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	// Validate before taking over the terminal, so errors stay visible
+	options, err := uiFlags.options()
+	if err != nil {
+		return err
+	}
+	options.SavePath = *savePath
+	options.NoSave = *noSave
+	options.Seed = rulesFlags.seed
+	if options.Rules, err = rulesFlags.rules(fs); err != nil {
+		return err
+	}
+	if *code != "" && (options.Rules != nil || options.Seed != 0) {
+		return errors.New("a code already has the rules and the seed")
+	}
+	if options.Seed != 0 && options.Rules == nil {
+		return errors.New("a seed needs a -mode or a custom game to play")
+	}
+	if *code != "" {
+		if _, err := game.DecodeCode(*code); err != nil {
+			return err
+		}
+	}
+	if err := options.Validate(); err != nil {
+		return err
+	}
+
+	var u *ui.Ui
+	if *code != "" {
+		u = ui.NewUiWithCode(*code, options)
+	} else if options.Rules != nil {
+		u = ui.NewUiWithGame(options)
+	} else {
+		u = ui.NewUiWithTitleMenu(options)
+	}
+
 	u.Loop()
+	return nil
+}
+
+func runVersion(args []string) error {
+	fs := newFlagSet("version", "")
+	if err := parseFlags(fs, args, 0); err != nil {
+		return err
+	}
+
+	v := version
+	if info, ok := debug.ReadBuildInfo(); ok && v == "dev" && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		v = info.Main.Version
+	}
+	fmt.Println("hsweeper", v)
+	return nil
 }
@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/borogk/hsweeper/game"
+	"github.com/gdamore/tcell/v2"
+)
+
+// AchievementsView displays all achievements, either unlocked or with progress towards them.
+type AchievementsView struct {
+	ui               *Ui
+	statsPath        string
+	achievementsPath string
+	stats            *game.Stats
+	achievements     *game.Achievements
+}
+
+const achievementsHeader = "    Achievement         Goal                                          Progress"
+
+func newAchievementsView(ui *Ui, statsPath, achievementsPath string) *AchievementsView {
+	return &AchievementsView{
+		ui:               ui,
+		statsPath:        statsPath,
+		achievementsPath: achievementsPath,
+	}
+}
+
+func (v *AchievementsView) OnActivate() {
+	v.stats = game.LoadStats(v.statsPath)
+	v.achievements = game.LoadAchievements(v.achievementsPath)
+}
+
+func (v *AchievementsView) OnDeactivate() {
+
+}
+
+func (v *AchievementsView) OnInput(key tcell.Key, rune rune) {
+	v.ui.popView()
+}
+
+func (v *AchievementsView) ContentSize() (width, height int) {
+	return len(achievementsHeader), len(game.AllAchievements) + 6
+}
+
+func (v *AchievementsView) Draw(screen tcell.Screen) {
+	screenWidth, screenHeight := screen.Size()
+	contentWidth, contentHeight := v.ContentSize()
+	palette := defaultPalette
+
+	x := (screenWidth - contentWidth) / 2
+	y := (screenHeight - contentHeight) / 2
+
+	unlockedCount := 0
+	for _, achievement := range game.AllAchievements {
+		if v.achievements.IsUnlocked(achievement.ID) {
+			unlockedCount++
+		}
+	}
+
+	screen.PutStrStyled(x, y, fmt.Sprintf("Achievements (%d/%d)", unlockedCount, len(game.AllAchievements)), palette.PlainText)
+	screen.PutStrStyled(x, y+2, achievementsHeader, palette.Border)
+	y += 3
+
+	for _, achievement := range game.AllAchievements {
+		marker := " "
+		progress := ""
+		style := palette.PlainText
+		if unlockedAt, ok := v.achievements.Unlocked[achievement.ID]; ok {
+			marker = glyphs.Achievement
+			progress = unlockedAt.Local().Format("2006-01-02")
+			style = palette.WinText
+		} else if achievement.Goal > 1 {
+			progress = fmt.Sprintf("%d/%d", achievement.Progress(v.stats), achievement.Goal)
+		}
+
+		line := fmt.Sprintf(" %s  %-18.18s  %-44.44s  %-10s", marker, achievement.Name, achievement.Description, progress)
+		screen.PutStrStyled(x, y, line, style)
+		y++
+	}
+
+	screen.PutStrStyled(x, y+1, "ESC  Back", palette.ExitText)
+}
@@ -0,0 +1,433 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/borogk/hsweeper/game"
+	"github.com/gdamore/tcell/v2"
+)
+
+// BoardStatus is a message displayed on top of a game field.
+type BoardStatus struct {
+	message  string
+	style    tcell.Style
+	centered bool
+}
+
+type (
+	// Density defines how wide game cells are drawn.
+	Density int
+
+	// Viewport is a visible part of a game field, measured in cells.
+	Viewport struct {
+		x         int
+		y         int
+		width     int
+		height    int
+		cellWidth int
+		// Width of row numbers on each side of the field, zero when rulers are off
+		rulerWidth int
+		// Lines reserved below the field for the minimap, zero when it's not shown
+		minimapHeight int
+	}
+)
+
+const (
+	DensityNormal Density = iota
+	DensityCompact
+	DensityDense
+)
+
+// Densities lists all densities in order of cycling through them, along with their names.
+var Densities = []struct {
+	Density Density
+	Name    string
+}{
+	{DensityNormal, "normal"},
+	{DensityCompact, "compact"},
+	{DensityDense, "dense"},
+}
+
+// Returns the width of a single cell in screen columns.
+func (d Density) cellWidth() int {
+	switch d {
+	case DensityCompact:
+		return 2
+	case DensityDense:
+		return 1
+	default:
+		return 3
+	}
+}
+
+// MarshalText stores density by its name in config files.
+func (d Density) MarshalText() ([]byte, error) {
+	for _, density := range Densities {
+		if density.Density == d {
+			return []byte(density.Name), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown density %d", d)
+}
+
+// UnmarshalText reads density by its name from config files.
+func (d *Density) UnmarshalText(text []byte) error {
+	for _, density := range Densities {
+		if density.Name == string(text) {
+			*d = density.Density
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown density %q", text)
+}
+
+// Returns the next density to cycle to.
+func (d Density) next() Density {
+	return (d + 1) % Density(len(Densities))
+}
+
+// Fits the viewport into the screen area, keeping its position as close as possible.
+// Rulers take a margin on both sides for row numbers and a line below for column numbers.
+// Minimap takes a few lines below the field, but only if the field doesn't fit entirely.
+func fitViewport(g *game.Game, screenWidth, screenHeight int, density Density, rulers, minimap bool, viewport Viewport) Viewport {
+	viewport.cellWidth = density.cellWidth()
+	viewport.rulerWidth = 0
+	if rulers {
+		viewport.rulerWidth = len(strconv.Itoa(g.Height())) + 1
+	}
+	viewport.minimapHeight = 0
+	viewport.width = max(min(g.Width(), (screenWidth-2-2*viewport.rulerWidth)/viewport.cellWidth), 1)
+	viewport.height = max(min(g.Height(), screenHeight-4-viewport.rulersHeight()), 1)
+	if minimap && viewport.isPartial(g) {
+		_, mapHeight := minimapSize(g, viewport)
+		viewport.minimapHeight = mapHeight + 1
+		viewport.height = max(min(g.Height(), screenHeight-4-viewport.rulersHeight()-viewport.minimapHeight), 1)
+	}
+	viewport.x = max(min(viewport.x, g.Width()-viewport.width), 0)
+	viewport.y = max(min(viewport.y, g.Height()-viewport.height), 0)
+	return viewport
+}
+
+// Scrolls the viewport as little as possible to make the cell visible.
+func (vp Viewport) follow(x, y int) Viewport {
+	vp.x = min(max(vp.x, x-vp.width+1), x)
+	vp.y = min(max(vp.y, y-vp.height+1), y)
+	return vp
+}
+
+// Scrolls the viewport to put the cell in its center, the result needs to be fit again.
+func (vp Viewport) centered(x, y int) Viewport {
+	vp.x = x - vp.width/2
+	vp.y = y - vp.height/2
+	return vp
+}
+
+// Returns the width of visible cells in screen columns.
+func (vp Viewport) fieldWidth() int {
+	return vp.width * vp.cellWidth
+}
+
+// Returns the amount of lines taken by column numbers below the field.
+func (vp Viewport) rulersHeight() int {
+	if vp.rulerWidth > 0 {
+		return 1
+	}
+	return 0
+}
+
+// Checks if the viewport doesn't show the entire game field.
+func (vp Viewport) isPartial(g *game.Game) bool {
+	return vp.width < g.Width() || vp.height < g.Height()
+}
+
+// Returns how much space a game field viewport takes along with its status line.
+func boardSize(viewport Viewport) (width, height int) {
+	return viewport.fieldWidth() + 2 + 2*viewport.rulerWidth, viewport.height + 4 + viewport.rulersHeight() + viewport.minimapHeight
+}
+
+// Draws a game field viewport with a status line on top, centered on screen.
+// Cursor is not shown if its coordinates are negative. Arrows on the border show where the field continues.
+// Returns a function to print over individual cells afterward, cells outside the viewport are skipped.
+func drawBoard(
+	screen tcell.Screen,
+	g *game.Game,
+	viewport Viewport,
+	palette Palette,
+	assist Assist,
+	status BoardStatus,
+	cursorX, cursorY int,
+) (printCell func(x, y int, symbol string, style tcell.Style)) {
+	screenWidth, screenHeight := screen.Size()
+	contentWidth, contentHeight := boardSize(viewport)
+
+	offsetX := (screenWidth-contentWidth)/2 + viewport.rulerWidth
+	offsetY := (screenHeight - contentHeight) / 2
+
+	// Status message on top of the game field
+	statusX := offsetX + 1
+	statusY := offsetY + 1
+	if status.centered {
+		statusX += (viewport.fieldWidth() - len(status.message)) / 2
+	}
+	screen.PutStrStyled(0, statusY, fmt.Sprintf("%*s", screenWidth, ""), palette.Blank)
+	screen.PutStrStyled(statusX, statusY, status.message, status.style)
+
+	// Game field border
+	borderLeft := offsetX
+	borderRight := borderLeft + viewport.fieldWidth() + 1
+	borderTop := statusY + 1
+	borderBottom := borderTop + viewport.height + 1
+	screen.Put(borderLeft, borderTop, glyphs.BorderTopLeft, palette.Border)
+	screen.Put(borderRight, borderTop, glyphs.BorderTopRight, palette.Border)
+	screen.Put(borderLeft, borderBottom, glyphs.BorderBottomLeft, palette.Border)
+	screen.Put(borderRight, borderBottom, glyphs.BorderBottomRight, palette.Border)
+	for x := borderLeft + 1; x < borderRight; x++ {
+		screen.Put(x, borderTop, glyphs.BorderHorizontal, palette.Border)
+		screen.Put(x, borderBottom, glyphs.BorderHorizontal, palette.Border)
+	}
+	for y := borderTop + 1; y < borderBottom; y++ {
+		screen.Put(borderLeft, y, glyphs.BorderVertical, palette.Border)
+		screen.Put(borderRight, y, glyphs.BorderVertical, palette.Border)
+	}
+
+	// Edge indicators
+	middleX := (borderLeft + borderRight) / 2
+	middleY := (borderTop + borderBottom) / 2
+	if viewport.y > 0 {
+		screen.Put(middleX, borderTop, glyphs.MoreUp, palette.PlainText)
+	}
+	if viewport.y+viewport.height < g.Height() {
+		screen.Put(middleX, borderBottom, glyphs.MoreDown, palette.PlainText)
+	}
+	if viewport.x > 0 {
+		screen.Put(borderLeft, middleY, glyphs.MoreLeft, palette.PlainText)
+	}
+	if viewport.x+viewport.width < g.Width() {
+		screen.Put(borderRight, middleY, glyphs.MoreRight, palette.PlainText)
+	}
+
+	if viewport.rulerWidth > 0 {
+		drawRulers(screen, g, viewport, palette, borderLeft, borderRight, borderTop, borderBottom, cursorX, cursorY)
+	}
+
+	printCell = func(x, y int, symbol string, style tcell.Style) {
+		x -= viewport.x
+		y -= viewport.y
+		if x < 0 || y < 0 || x >= viewport.width || y >= viewport.height {
+			return
+		}
+
+		cellX := borderLeft + 1 + x*viewport.cellWidth
+		cellY := borderTop + 1 + y
+		switch viewport.cellWidth {
+		case 3:
+			symbol = " " + symbol + " "
+		case 2:
+			symbol = symbol + " "
+		}
+		screen.PutStrStyled(cellX, cellY, symbol, style)
+	}
+
+	// Game cells
+	for x := viewport.x; x < viewport.x+viewport.width; x++ {
+		for y := viewport.y; y < viewport.y+viewport.height; y++ {
+			symbol, style := cellAppearance(g, x, y, palette)
+			style = assistStyle(g, x, y, palette, assist, style)
+			if x == cursorX && y == cursorY {
+				style = palette.Cursor
+			}
+			printCell(x, y, symbol, style)
+		}
+	}
+
+	return printCell
+}
+
+// Draws 1-based row numbers on both sides of the field and column numbers below it.
+// Columns are numbered sparsely when cells are too narrow, the cursor's row and column are always numbered and highlighted.
+func drawRulers(
+	screen tcell.Screen,
+	g *game.Game,
+	viewport Viewport,
+	palette Palette,
+	borderLeft, borderRight, borderTop, borderBottom int,
+	cursorX, cursorY int,
+) {
+	screenWidth, _ := screen.Size()
+	labelStyle := func(highlighted bool) tcell.Style {
+		if highlighted {
+			return palette.PlainText
+		}
+		return palette.Border
+	}
+
+	for y := viewport.y; y < viewport.y+viewport.height; y++ {
+		screenY := borderTop + 1 + y - viewport.y
+		style := labelStyle(y == cursorY)
+		screen.PutStrStyled(borderLeft-viewport.rulerWidth, screenY, fmt.Sprintf("%*d ", viewport.rulerWidth-1, y+1), style)
+		screen.PutStrStyled(borderRight+1, screenY, fmt.Sprintf(" %-*d", viewport.rulerWidth-1, y+1), style)
+	}
+
+	// Labels need at least one blank column between them
+	labelWidth := len(strconv.Itoa(g.Width())) + 1
+	step := 1
+	for _, s := range []int{1, 2, 5, 10, 20, 50, 100} {
+		step = s
+		if s*viewport.cellWidth >= labelWidth {
+			break
+		}
+	}
+
+	rulerY := borderBottom + 1
+	screen.PutStrStyled(0, rulerY, fmt.Sprintf("%*s", screenWidth, ""), palette.Blank)
+	printLabel := func(x int, style tcell.Style) {
+		labelX := borderLeft + 1 + (x-viewport.x)*viewport.cellWidth
+		if viewport.cellWidth == 3 {
+			labelX++
+		}
+		screen.PutStrStyled(labelX, rulerY, strconv.Itoa(x+1), style)
+	}
+	for x := viewport.x; x < viewport.x+viewport.width; x++ {
+		if (x+1)%step == 0 || x == viewport.x {
+			printLabel(x, labelStyle(false))
+		}
+	}
+	if cursorX >= viewport.x && cursorX < viewport.x+viewport.width {
+		printLabel(cursorX, labelStyle(true))
+	}
+}
+
+// Translates screen coordinates into a game cell, using the same layout as drawBoard.
+func boardCellAt(screen tcell.Screen, g *game.Game, viewport Viewport, screenX, screenY int) (x, y int, ok bool) {
+	screenWidth, screenHeight := screen.Size()
+	contentWidth, contentHeight := boardSize(viewport)
+
+	cellsLeft := (screenWidth-contentWidth)/2 + viewport.rulerWidth + 1
+	cellsTop := (screenHeight-contentHeight)/2 + 3
+	if screenX < cellsLeft || screenY < cellsTop {
+		return 0, 0, false
+	}
+
+	x = (screenX - cellsLeft) / viewport.cellWidth
+	y = screenY - cellsTop
+	if x >= viewport.width || y >= viewport.height {
+		return 0, 0, false
+	}
+
+	return viewport.x + x, viewport.y + y, true
+}
+
+// Returns the size of the minimap, it's never wider than the field to stay within the board.
+func minimapSize(g *game.Game, viewport Viewport) (width, height int) {
+	const maxWidth, maxHeight = 24, 8
+	return min(g.Width(), maxWidth, viewport.fieldWidth()), min(g.Height(), maxHeight)
+}
+
+// Draws a small map of the whole game field below its bottom-right corner, highlighting the viewport.
+// Does nothing unless fitViewport reserved space for it.
+func drawMinimap(screen tcell.Screen, g *game.Game, viewport Viewport, palette Palette) {
+	if viewport.minimapHeight == 0 {
+		return
+	}
+
+	screenWidth, screenHeight := screen.Size()
+	contentWidth, contentHeight := boardSize(viewport)
+	mapWidth, mapHeight := minimapSize(g, viewport)
+	mapLeft := (screenWidth-contentWidth)/2 + viewport.rulerWidth + 1 + viewport.fieldWidth() - mapWidth
+	mapTop := (screenHeight-contentHeight)/2 + contentHeight - mapHeight
+
+	for mapY := 0; mapY < mapHeight; mapY++ {
+		for mapX := 0; mapX < mapWidth; mapX++ {
+			// Each map symbol covers a block of cells, it's highlighted if any of them are visible
+			fromX, toX := mapX*g.Width()/mapWidth, (mapX+1)*g.Width()/mapWidth
+			fromY, toY := mapY*g.Height()/mapHeight, (mapY+1)*g.Height()/mapHeight
+			symbol := glyphs.MinimapHidden
+			if fromX < viewport.x+viewport.width && toX > viewport.x && fromY < viewport.y+viewport.height && toY > viewport.y {
+				symbol = glyphs.MinimapVisible
+			}
+			screen.Put(mapLeft+mapX, mapTop+mapY, symbol, palette.PlainText)
+		}
+	}
+}
+
+// Returns a single symbol representing the cell, it's padded according to density when printed.
+func cellAppearance(g *game.Game, x, y int, palette Palette) (symbol string, style tcell.Style) {
+	symbol = " "
+	style = palette.Blank
+
+	cell := g.Cell(x, y)
+	if cell.IsRevealed() {
+		if cell.IsMine() {
+			symbol = glyphs.Mine
+			style = palette.RevealedMine
+		} else if cell.AdjacentMines() > 0 {
+			symbol = string(rune('0' + cell.AdjacentMines()))
+			style = palette.Numbers[cell.AdjacentMines()%len(palette.Numbers)]
+		} else if cell.IsHeart() {
+			symbol = glyphs.Heart
+			style = palette.Heart
+		}
+	} else if cell.IsQuestioned() {
+		symbol = glyphs.Question
+		style = palette.Question
+	} else if cell.IsFlagged() {
+		symbol = glyphs.Flag
+		style = palette.Flag
+		if g.Status() == game.StatusLost && !cell.IsMine() {
+			style = palette.WrongFlag
+		}
+	} else if cell.IsMine() && g.IsFinished() {
+		symbol = glyphs.Mine
+		style = palette.UnrevealedMine
+	} else {
+		symbol = glyphs.Unrevealed
+		style = palette.Unrevealed
+	}
+
+	return
+}
+
+// Highlights numbers of a game in progress according to the assist level. Solved numbers are dimmed,
+// numbers with too many flags around are marked as mistakes. Revealed mines count as flags.
+func assistStyle(g *game.Game, x, y int, palette Palette, assist Assist, style tcell.Style) tcell.Style {
+	cell := g.Cell(x, y)
+	if assist == AssistOff || g.Status() != game.StatusStarted || !cell.IsRevealed() || cell.AdjacentMines() == 0 {
+		return style
+	}
+
+	flags, unresolved := 0, 0
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			if (dx == 0 && dy == 0) || g.IsOutOfBounds(x+dx, y+dy) {
+				continue
+			}
+
+			adjacent := g.Cell(x+dx, y+dy)
+			if adjacent.IsFlagged() || (adjacent.IsRevealed() && adjacent.IsMine()) {
+				flags++
+			} else if !adjacent.IsRevealed() {
+				unresolved++
+			}
+		}
+	}
+
+	if assist >= AssistMistakes && flags > cell.AdjacentMines() {
+		return palette.MistakeNumber
+	} else if flags == cell.AdjacentMines() && unresolved == 0 {
+		return palette.SolvedNumber
+	}
+
+	return style
+}
+
+// Draws a message on the blank line above the status, centered over the game field. Empty message clears the line.
+func drawBoardNotice(screen tcell.Screen, viewport Viewport, palette Palette, message string, style tcell.Style) {
+	screenWidth, screenHeight := screen.Size()
+	contentWidth, contentHeight := boardSize(viewport)
+
+	noticeX := (screenWidth-contentWidth)/2 + viewport.rulerWidth + 1 + (viewport.fieldWidth()-len([]rune(message)))/2
+	noticeY := (screenHeight - contentHeight) / 2
+	screen.PutStrStyled(0, noticeY, fmt.Sprintf("%*s", screenWidth, ""), palette.Blank)
+	screen.PutStrStyled(max(noticeX, 0), noticeY, message, style)
+}
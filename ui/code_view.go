@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/borogk/hsweeper/game"
+	"github.com/gdamore/tcell/v2"
+)
+
+// CodeView displays a shareable code of a game.
+type CodeView struct {
+	ui           *Ui
+	game         *game.Game
+	withProgress bool
+	code         string
+}
+
+func newCodeView(ui *Ui, g *game.Game) *CodeView {
+	view := &CodeView{
+		ui:   ui,
+		game: g,
+	}
+	view.code = g.EncodeCode(false)
+	return view
+}
+
+func (v *CodeView) OnActivate() {
+
+}
+
+func (v *CodeView) OnDeactivate() {
+
+}
+
+func (v *CodeView) OnInput(key tcell.Key, rune rune) {
+	if unicode.ToLower(rune) == 'p' {
+		v.withProgress = !v.withProgress
+		v.code = v.game.EncodeCode(v.withProgress)
+		v.ui.fullRefresh()
+	} else {
+		v.ui.popView()
+	}
+}
+
+func (v *CodeView) ContentSize() (width, height int) {
+	lineWidth := v.lineWidth()
+	return lineWidth, (len(v.code)+lineWidth-1)/lineWidth + 6
+}
+
+func (v *CodeView) Draw(screen tcell.Screen) {
+	screenWidth, screenHeight := screen.Size()
+	contentWidth, contentHeight := v.ContentSize()
+	palette := defaultPalette
+
+	x := (screenWidth - contentWidth) / 2
+	y := (screenHeight - contentHeight) / 2
+
+	screen.PutStrStyled(x, y, "Board code", palette.PlainText)
+	y += 2
+
+	// Decoding ignores line breaks, so wrapped code can be copied straight from the terminal
+	for i := 0; i < len(v.code); i += contentWidth {
+		screen.PutStrStyled(x, y, v.code[i:min(i+contentWidth, len(v.code))], palette.ReadyText)
+		y++
+	}
+	y++
+
+	progress := "off"
+	if v.withProgress {
+		progress = "on"
+	}
+	screen.PutStrStyled(x, y, fmt.Sprintf("P    Include progress [%s]", progress), palette.PlainText)
+	screen.PutStrStyled(x, y+1, "ESC  Back", palette.ExitText)
+}
+
+// Codes are wrapped to fit the screen width, but are never narrower than the hints below them.
+func (v *CodeView) lineWidth() int {
+	screenWidth, _ := v.ui.screen.Size()
+	return max(min(len(v.code), screenWidth-4), 28)
+}
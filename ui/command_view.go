@@ -0,0 +1,153 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// CommandView is a command line at the bottom of the screen, drawn over the view underneath.
+// Commands typed before are kept for the session and can be browsed with up and down keys.
+type CommandView struct {
+	Overlay
+	ui       *Ui
+	text     []rune
+	message  string
+	history  int
+	complete func(text string) []string
+	onSubmit func(text string)
+}
+
+// How many commands are remembered.
+const maxCommandHistory = 100
+
+// Creates a command line. Complete returns whole lines starting with the text for TAB completion,
+// onSubmit is called with the typed command after the command line is closed.
+func newCommandView(ui *Ui, complete func(text string) []string, onSubmit func(text string)) *CommandView {
+	return &CommandView{
+		ui:       ui,
+		history:  len(ui.commandHistory),
+		complete: complete,
+		onSubmit: onSubmit,
+	}
+}
+
+func (v *CommandView) OnActivate() {
+
+}
+
+func (v *CommandView) OnDeactivate() {
+
+}
+
+func (v *CommandView) OnInput(key tcell.Key, rune rune) {
+	switch key {
+	case tcell.KeyEscape:
+		v.ui.popView()
+		return
+	case tcell.KeyEnter:
+		v.submit()
+		return
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		// Like in vim, erasing past the start closes the command line
+		if len(v.text) == 0 {
+			v.ui.popView()
+			return
+		}
+		v.text = v.text[:len(v.text)-1]
+		v.message = ""
+	case tcell.KeyCtrlU:
+		v.text = nil
+		v.message = ""
+	case tcell.KeyTab:
+		v.completeText()
+	case tcell.KeyUp:
+		v.browseHistory(-1)
+	case tcell.KeyDown:
+		v.browseHistory(1)
+	case tcell.KeyRune:
+		if unicode.IsPrint(rune) {
+			v.text = append(v.text, rune)
+			v.message = ""
+		}
+	}
+	v.ui.fullRefresh()
+}
+
+func (v *CommandView) ContentSize() (width, height int) {
+	return promptWidth, 2
+}
+
+// Draws the command line on the last screen line, with the message above it.
+func (v *CommandView) Draw(screen tcell.Screen) {
+	screenWidth, screenHeight := screen.Size()
+	palette := defaultPalette
+
+	// Only the tail of a long text fits, leave room for the colon and the cursor
+	visibleText := v.text
+	if len(visibleText) > screenWidth-2 {
+		visibleText = visibleText[len(visibleText)-screenWidth+2:]
+	}
+
+	if v.message != "" {
+		screen.PutStrStyled(0, screenHeight-2, fmt.Sprintf("%-*.*s", screenWidth, screenWidth, v.message), palette.Border)
+	}
+	screen.PutStrStyled(0, screenHeight-1, fmt.Sprintf("%-*s", screenWidth, ":"+string(visibleText)+"_"), palette.ReadyText)
+}
+
+// Remembers the command and runs it, an empty line just closes the command line.
+func (v *CommandView) submit() {
+	text := strings.TrimSpace(string(v.text))
+	v.ui.popView()
+	if text == "" {
+		return
+	}
+
+	history := v.ui.commandHistory
+	if len(history) == 0 || history[len(history)-1] != text {
+		history = append(history, text)
+	}
+	v.ui.commandHistory = history[max(len(history)-maxCommandHistory, 0):]
+	v.onSubmit(text)
+}
+
+// Completes the text as far as all candidates agree, listing them if there are several.
+func (v *CommandView) completeText() {
+	candidates := v.complete(string(v.text))
+	switch len(candidates) {
+	case 0:
+		v.message = "No completions"
+	case 1:
+		v.text = []rune(candidates[0] + " ")
+		v.message = ""
+	default:
+		prefix := candidates[0]
+		for _, candidate := range candidates[1:] {
+			for !strings.HasPrefix(candidate, prefix) {
+				prefix = prefix[:len(prefix)-1]
+			}
+		}
+		v.text = []rune(prefix)
+
+		// Only the word being completed is listed
+		words := make([]string, 0, len(candidates))
+		for _, candidate := range candidates {
+			words = append(words, candidate[strings.LastIndex(candidate, " ")+1:])
+		}
+		v.message = strings.Join(words, "  ")
+	}
+}
+
+// Replaces the text with an older or newer command, going past the newest one gives an empty line.
+func (v *CommandView) browseHistory(step int) {
+	history := v.ui.commandHistory
+	v.history = max(min(v.history+step, len(history)), 0)
+	if v.history < len(history) {
+		v.text = []rune(history[v.history])
+	} else {
+		v.text = nil
+	}
+	v.message = ""
+}
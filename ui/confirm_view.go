@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// ConfirmView asks a yes or no question over the view underneath.
+type ConfirmView struct {
+	Overlay
+	ui        *Ui
+	question  []string
+	onConfirm func()
+}
+
+const confirmHint = "Y  Yes    N  No"
+
+// Creates a confirmation, onConfirm is called after the dialog is closed with yes. Question may span several lines.
+func newConfirmView(ui *Ui, onConfirm func(), question ...string) *ConfirmView {
+	return &ConfirmView{
+		ui:        ui,
+		question:  question,
+		onConfirm: onConfirm,
+	}
+}
+
+func (v *ConfirmView) OnActivate() {
+
+}
+
+func (v *ConfirmView) OnDeactivate() {
+
+}
+
+func (v *ConfirmView) OnInput(key tcell.Key, rune rune) {
+	action, _ := v.ui.keys.Action(key, rune)
+	switch {
+	case key == tcell.KeyRune && unicode.ToLower(rune) == 'y':
+		v.ui.popView()
+		v.onConfirm()
+	case key == tcell.KeyRune && unicode.ToLower(rune) == 'n', action == ActionBack:
+		v.ui.popView()
+	}
+}
+
+func (v *ConfirmView) ContentSize() (width, height int) {
+	width, height = v.size()
+	return width + 4, height + 2
+}
+
+func (v *ConfirmView) Draw(screen tcell.Screen) {
+	palette := defaultPalette
+	width, height := v.size()
+	x, y := drawOverlayBox(screen, width, height, palette)
+	for i, line := range v.question {
+		screen.PutStrStyled(x, y+i, line, palette.PlainText)
+	}
+	screen.PutStrStyled(x, y+height-1, confirmHint, palette.ExitText)
+}
+
+// Returns size of the dialog contents: the question, an empty line and the hint.
+func (v *ConfirmView) size() (width, height int) {
+	width = len(confirmHint)
+	for _, line := range v.question {
+		width = max(width, len([]rune(line)))
+	}
+	return width, len(v.question) + 2
+}
+
+// Asks to confirm a destructive action, unless confirmations are turned off in settings.
+func (u *Ui) confirm(onConfirm func(), question ...string) {
+	if u.settings.Confirmations {
+		u.pushView(newConfirmView(u, onConfirm, question...))
+	} else {
+		onConfirm()
+	}
+}
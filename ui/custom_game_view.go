@@ -0,0 +1,367 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/borogk/hsweeper/game"
+	"github.com/gdamore/tcell/v2"
+)
+
+type (
+	// CustomGameView is a form to set up and start a game with custom rules.
+	CustomGameView struct {
+		ui              *Ui
+		customGamesPath string
+		customGames     *game.CustomGames
+		fields          []*CustomGameField
+		firstClick      game.FirstClick
+		heartSpawning   game.HeartSpawning
+		cursor          int
+		onStart         func(rules game.Rules)
+	}
+
+	// CustomGameField is a text field of the custom game form.
+	CustomGameField struct {
+		label string
+		text  []rune
+		err   error
+	}
+)
+
+// Rows of the form, text fields go first.
+const (
+	customGameWidth = iota
+	customGameHeight
+	customGameMines
+	customGameHearts
+	customGameLives
+	customGameFirstClick
+	customGameHeartSpawning
+	customGameStart
+	customGameSavePreset
+	customGameRows
+)
+
+// Explains heart spawning strategies next to the selected one.
+var heartSpawningHints = map[game.HeartSpawning]string{
+	game.HeartSpawningCounter:  "evenly over isolated cells",
+	game.HeartSpawningRandom:   "hidden in random isolated cells",
+	game.HeartSpawningProgress: "as the board is cleared",
+	game.HeartSpawningLogic:    "earned by proven safe reveals",
+}
+
+const (
+	customGameFormWidth = 72
+	minCustomGameSize   = 5
+	maxCustomGameSize   = 500
+	maxPresetNameWidth  = 16
+)
+
+// Creates the form filled with the last custom rules, onStart is called with valid rules to start the game.
+func newCustomGameView(ui *Ui, customGamesPath string, onStart func(rules game.Rules)) *CustomGameView {
+	view := &CustomGameView{
+		ui:              ui,
+		customGamesPath: customGamesPath,
+		customGames:     game.LoadCustomGames(customGamesPath),
+		onStart:         onStart,
+	}
+
+	last := view.customGames.Last
+	if last.Width == 0 {
+		last = game.Rules{Width: 30, Height: 16, Mines: 99, Lives: 1}
+	}
+
+	view.fields = []*CustomGameField{
+		{label: "Width", text: []rune(strconv.Itoa(last.Width))},
+		{label: "Height", text: []rune(strconv.Itoa(last.Height))},
+		{label: "Mines", text: []rune(strconv.Itoa(last.Mines))},
+		{label: "Hearts", text: []rune(strconv.Itoa(last.Hearts))},
+		{label: "Lives", text: []rune(strconv.Itoa(last.Lives))},
+	}
+	view.firstClick = last.FirstClick
+	view.heartSpawning = last.HeartSpawning
+	view.validate()
+	return view
+}
+
+func (v *CustomGameView) OnActivate() {
+
+}
+
+func (v *CustomGameView) OnDeactivate() {
+
+}
+
+func (v *CustomGameView) OnInput(key tcell.Key, rune rune) {
+	switch key {
+	case tcell.KeyEscape:
+		v.ui.popView()
+	case tcell.KeyUp, tcell.KeyBacktab:
+		v.cursor = (v.cursor - 1 + customGameRows) % customGameRows
+	case tcell.KeyDown, tcell.KeyTab:
+		v.cursor = (v.cursor + 1) % customGameRows
+	case tcell.KeyLeft:
+		v.changeOption(-1)
+	case tcell.KeyRight:
+		v.changeOption(1)
+	case tcell.KeyEnter:
+		if v.cursor == customGameSavePreset {
+			v.promptPresetName()
+		} else {
+			v.start()
+		}
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if field := v.currentField(); field != nil && len(field.text) > 0 {
+			field.text = field.text[:len(field.text)-1]
+		}
+	case tcell.KeyRune:
+		if field := v.currentField(); field != nil && (unicode.IsDigit(rune) || rune == '.' || rune == '%') {
+			field.text = append(field.text, rune)
+		} else if rune == ' ' {
+			v.changeOption(1)
+		}
+	}
+
+	v.validate()
+	v.ui.fullRefresh()
+}
+
+func (v *CustomGameView) ContentSize() (width, height int) {
+	return customGameFormWidth, customGameRows + 9
+}
+
+func (v *CustomGameView) Draw(screen tcell.Screen) {
+	screenWidth, screenHeight := screen.Size()
+	contentWidth, contentHeight := v.ContentSize()
+	palette := defaultPalette
+
+	x := (screenWidth - contentWidth) / 2
+	y := (screenHeight - contentHeight) / 2
+
+	marker := func(row int) string {
+		if row == v.cursor {
+			return glyphs.Selected
+		}
+		return " "
+	}
+
+	screen.PutStrStyled(x, y, "Custom game", palette.PlainText)
+	y += 2
+
+	for i, field := range v.fields {
+		value := string(field.text)
+		if i == v.cursor {
+			value += "_"
+		}
+		screen.PutStrStyled(x, y, fmt.Sprintf("%s %-12s %-8s", marker(i), field.label, value), palette.PlainText)
+		if field.err != nil {
+			screen.PutStrStyled(x+24, y, field.err.Error(), palette.LoseText)
+		} else if i == customGameMines {
+			screen.PutStrStyled(x+24, y, "count or density, e.g. 20%", palette.Border)
+		}
+		y++
+	}
+
+	firstClick := fmt.Sprintf(
+		"%s %-12s %s %s %s",
+		marker(customGameFirstClick),
+		"First click",
+		glyphs.Previous,
+		v.firstClick,
+		glyphs.Next,
+	)
+	screen.PutStrStyled(x, y, firstClick, palette.PlainText)
+	y++
+
+	heartSpawning := fmt.Sprintf(
+		"%s %-12s %s %s %s",
+		marker(customGameHeartSpawning),
+		"Hearts spawn",
+		glyphs.Previous,
+		v.heartSpawning,
+		glyphs.Next,
+	)
+	screen.PutStrStyled(x, y, heartSpawning, palette.PlainText)
+	screen.PutStrStyled(x+30, y, heartSpawningHints[v.heartSpawning], palette.Border)
+	y += 2
+
+	if rules, ok := v.rules(); ok {
+		screen.PutStrStyled(x+2, y, "Difficulty: "+estimateDifficulty(rules), palette.ReadyText)
+	} else {
+		screen.PutStrStyled(x+2, y, "Fix the errors to start", palette.LoseText)
+	}
+	y += 2
+
+	screen.PutStrStyled(x, y, marker(customGameStart)+" Start game", palette.ClassicGameText)
+	screen.PutStrStyled(x, y+1, marker(customGameSavePreset)+" Save as preset", palette.ClassicGameText)
+	y += 3
+
+	hint := fmt.Sprintf("%s Select   %s Change   ENTER Start   ESC Back", glyphs.KeysUpDown, glyphs.KeysLeftRight)
+	screen.PutStrStyled(x, y, hint, palette.ExitText)
+}
+
+// Cycles the option under the cursor forward or backward, other rows are left alone.
+func (v *CustomGameView) changeOption(step int) {
+	switch v.cursor {
+	case customGameFirstClick:
+		v.firstClick = (v.firstClick + game.FirstClick(3+step)) % 3
+	case customGameHeartSpawning:
+		v.heartSpawning = (v.heartSpawning + game.HeartSpawning(4+step)) % 4
+	}
+}
+
+// Returns the text field under the cursor, or nil if it's another row.
+func (v *CustomGameView) currentField() *CustomGameField {
+	if v.cursor < len(v.fields) {
+		return v.fields[v.cursor]
+	}
+	return nil
+}
+
+// Checks all fields, remembering errors in them.
+func (v *CustomGameView) validate() {
+	width, widthErr := parseCustomGameNumber(v.fields[customGameWidth].text, minCustomGameSize, maxCustomGameSize)
+	height, heightErr := parseCustomGameNumber(v.fields[customGameHeight].text, minCustomGameSize, maxCustomGameSize)
+	_, v.fields[customGameHearts].err = parseCustomGameNumber(v.fields[customGameHearts].text, 0, 999)
+	_, v.fields[customGameLives].err = parseCustomGameNumber(v.fields[customGameLives].text, 1, 99)
+	v.fields[customGameWidth].err = widthErr
+	v.fields[customGameHeight].err = heightErr
+
+	// Mines can only be checked against a valid size
+	if widthErr == nil && heightErr == nil {
+		_, v.fields[customGameMines].err = parseCustomGameMines(v.fields[customGameMines].text, width*height, v.firstClick)
+	} else {
+		v.fields[customGameMines].err = nil
+	}
+}
+
+// Returns rules set up in the form, ok is false if any field is invalid.
+func (v *CustomGameView) rules() (rules game.Rules, ok bool) {
+	for _, field := range v.fields {
+		if field.err != nil {
+			return rules, false
+		}
+	}
+
+	rules.Width, _ = parseCustomGameNumber(v.fields[customGameWidth].text, minCustomGameSize, maxCustomGameSize)
+	rules.Height, _ = parseCustomGameNumber(v.fields[customGameHeight].text, minCustomGameSize, maxCustomGameSize)
+	rules.Mines, _ = parseCustomGameMines(v.fields[customGameMines].text, rules.Width*rules.Height, v.firstClick)
+	rules.Hearts, _ = parseCustomGameNumber(v.fields[customGameHearts].text, 0, 999)
+	rules.Lives, _ = parseCustomGameNumber(v.fields[customGameLives].text, 1, 99)
+	rules.FirstClick = v.firstClick
+	rules.HeartSpawning = v.heartSpawning
+	return rules, true
+}
+
+// Remembers valid rules as the last custom game and starts it.
+func (v *CustomGameView) start() {
+	rules, ok := v.rules()
+	if !ok {
+		return
+	}
+
+	v.customGames.Last = rules
+	_ = v.customGames.Save(v.customGamesPath)
+	v.ui.popView()
+	v.onStart(rules)
+}
+
+// Asks for a name to save valid rules as a preset, which then appears in the title menu.
+func (v *CustomGameView) promptPresetName() {
+	rules, ok := v.rules()
+	if !ok {
+		return
+	}
+
+	v.ui.pushView(newPromptView(v.ui, "Preset name (ESC to cancel):", "", func(name string) error {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return errors.New("name can't be empty")
+		}
+		if len([]rune(name)) > maxPresetNameWidth {
+			return fmt.Errorf("name can't be longer than %d symbols", maxPresetNameWidth)
+		}
+		if slices.Contains(builtInModes, name) {
+			return errors.New("name is taken by a built-in mode")
+		}
+
+		rules.Mode = name
+		v.customGames.SavePreset(rules)
+		_ = v.customGames.Save(v.customGamesPath)
+		v.ui.popView()
+		return nil
+	}))
+}
+
+// Parses a whole number within limits.
+func parseCustomGameNumber(text []rune, minValue, maxValue int) (int, error) {
+	value, err := strconv.Atoi(string(text))
+	if err != nil {
+		return 0, errors.New("must be a whole number")
+	}
+	if value < minValue || value > maxValue {
+		return 0, fmt.Errorf("must be from %d to %d", minValue, maxValue)
+	}
+
+	return value, nil
+}
+
+// Parses mines either as a count or as a density in percent. Cells protected by first click policy can't have mines.
+func parseCustomGameMines(text []rune, cells int, firstClick game.FirstClick) (int, error) {
+	protected := 0
+	switch firstClick {
+	case game.FirstClickOpening:
+		protected = 9
+	case game.FirstClickSafe:
+		protected = 1
+	}
+	maxMines := cells - max(protected, 1)
+
+	if percent, ok := strings.CutSuffix(string(text), "%"); ok {
+		density, err := strconv.ParseFloat(percent, 64)
+		if err != nil || density <= 0 || density >= 100 {
+			return 0, errors.New("density must be between 0% and 100%")
+		}
+
+		mines := max(int(math.Round(float64(cells)*density/100)), 1)
+		if mines > maxMines {
+			return 0, fmt.Errorf("too dense, at most %d mines fit", maxMines)
+		}
+		return mines, nil
+	}
+
+	return parseCustomGameNumber(text, 1, maxMines)
+}
+
+// Roughly estimates difficulty by mine density, comparing it to classic modes. Extra lives make it easier.
+func estimateDifficulty(rules game.Rules) string {
+	cells := rules.Width * rules.Height
+	density := float64(rules.Mines) / float64(cells)
+
+	level := "Extreme"
+	switch {
+	case density < 0.10:
+		level = "Beginner"
+	case density < 0.14:
+		level = "Easy (like Classic Easy)"
+	case density < 0.18:
+		level = "Medium (like Classic Medium)"
+	case density < 0.22:
+		level = "Hard (like Classic Expert)"
+	case density < 0.30:
+		level = "Very hard"
+	}
+
+	estimate := fmt.Sprintf("%s, %.1f%% mines", level, density*100)
+	if extraLives := rules.Lives - 1 + rules.Hearts; extraLives > 0 {
+		estimate += fmt.Sprintf(", eased by up to %d extra lives", extraLives)
+	}
+
+	return estimate
+}
@@ -0,0 +1,358 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/borogk/hsweeper/game"
+)
+
+// GameCommand is a command typed into the command line of a game. Arguments are separated by spaces.
+type GameCommand struct {
+	name        string
+	usage       string
+	description string
+	// Values of the first argument offered by completion, nil if there are none
+	arguments func() []string
+	run       func(args []string) error
+}
+
+var saveSlotPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// Lists commands available in the game. Cell coordinates are 1-based, the same as shown by the rulers.
+func (v *GameView) commands() []GameCommand {
+	return []GameCommand{
+		{
+			name:        "reveal",
+			usage:       "COLUMN ROW",
+			description: "Reveal the cell",
+			run: v.atLocation(func() {
+				v.forceReveal()
+			}),
+		},
+		{
+			name:        "flag",
+			usage:       "COLUMN ROW",
+			description: "Toggle flag on the cell",
+			run: v.atLocation(func() {
+				v.game.ToggleFlag(v.cx, v.cy)
+				v.onGameAction()
+			}),
+		},
+		{
+			name:        "chord",
+			usage:       "COLUMN ROW",
+			description: "Reveal around the number with enough flags",
+			run: v.atLocation(func() {
+				if v.game.Cell(v.cx, v.cy).IsRevealed() {
+					v.advancedReveal()
+					v.onGameAction()
+				}
+			}),
+		},
+		{
+			name:        "pickup",
+			usage:       "COLUMN ROW",
+			description: "Pick up the heart",
+			run: v.atLocation(func() {
+				v.game.Pickup(v.cx, v.cy)
+				v.onGameAction()
+			}),
+		},
+		{
+			name:        "goto",
+			usage:       "COLUMN ROW",
+			description: "Move the cursor to the cell",
+			run:         v.atLocation(func() {}),
+		},
+		{
+			name:        "hint",
+			description: "Move to the closest cell proven safe",
+			run:         func(args []string) error { return v.hint() },
+		},
+		{
+			name:        "seed",
+			description: "Show the seed of the mine layout",
+			run: func(args []string) error {
+				if v.game.Seed() == 0 {
+					return errors.New("the game has no seed")
+				}
+				v.setNotice(fmt.Sprintf("Seed: %d", v.game.Seed()))
+				return nil
+			},
+		},
+		{
+			name:        "export",
+			usage:       "[code|progress]",
+			description: "Show the board code, maybe with progress",
+			arguments:   func() []string { return []string{"code", "progress"} },
+			run: func(args []string) error {
+				view := newCodeView(v.ui, v.game)
+				switch strings.Join(args, " ") {
+				case "", "code":
+				case "progress":
+					view.withProgress = true
+					view.code = v.game.EncodeCode(true)
+				default:
+					return errors.New("export either code or progress")
+				}
+				v.ui.pushView(view)
+				return nil
+			},
+		},
+		{
+			name:        "save",
+			usage:       "SLOT",
+			description: "Save the game into a named slot",
+			arguments:   saveSlots,
+			run: func(args []string) error {
+				slot, err := parseSaveSlot(args)
+				if err != nil {
+					return err
+				}
+				if err := saveToSlot(v.game, slot); err != nil {
+					return fmt.Errorf("can't save: %w", err)
+				}
+				v.setNotice("Saved to " + slot)
+				return nil
+			},
+		},
+		{
+			name:        "load",
+			usage:       "SLOT",
+			description: "Load the game from a named slot",
+			arguments:   saveSlots,
+			run: func(args []string) error {
+				slot, err := parseSaveSlot(args)
+				if err != nil {
+					return err
+				}
+				g := game.LoadGame(saveSlotPath(slot))
+				if g == nil {
+					return fmt.Errorf("no game saved in %s", slot)
+				}
+				// Loading after a loss would undo it, so loaded games don't count for records either
+				g.MarkRetry()
+				v.replaceGame(func() { v.setGame(g) }, "Load "+slot+"?")
+				return nil
+			},
+		},
+		{
+			name:        "undo",
+			usage:       "[MOVES]",
+			description: "Take back moves, no records after that",
+			run: func(args []string) error {
+				moves := 1
+				if len(args) > 0 {
+					var err error
+					if moves, err = strconv.Atoi(args[0]); err != nil || moves < 1 || len(args) > 1 {
+						return errors.New("undo takes a positive number of moves")
+					}
+				}
+				return v.undo(moves)
+			},
+		},
+		{
+			name:        "stats",
+			description: "Show statistics",
+			run: func(args []string) error {
+				v.ui.pushView(newStatsView(v.ui, v.statsPath))
+				return nil
+			},
+		},
+		{
+			name:        "help",
+			description: "List commands",
+			run: func(args []string) error {
+				v.ui.pushView(newCommandHelpView(v.ui, v.commands()))
+				return nil
+			},
+		},
+		{
+			name:        "quit",
+			description: "Quit to menu",
+			run: func(args []string) error {
+				v.quit()
+				return nil
+			},
+		},
+	}
+}
+
+// Opens the command line.
+func (v *GameView) openCommandLine() {
+	v.ui.pushView(newCommandView(v.ui, v.completeCommand, v.runCommand))
+}
+
+// Runs a command line, errors are shown as a notice. Commands may be shortened to any unambiguous prefix.
+func (v *GameView) runCommand(text string) {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return
+	}
+
+	matches := v.matchCommands(fields[0])
+	if len(matches) == 0 {
+		v.setNotice(fmt.Sprintf("Unknown command %q, type :help to list commands", fields[0]))
+		return
+	} else if len(matches) > 1 {
+		v.setNotice(fmt.Sprintf("Ambiguous command %q, TAB lists the matching ones", fields[0]))
+		return
+	}
+
+	if err := matches[0].run(fields[1:]); err != nil {
+		v.setNotice(matches[0].name + ": " + err.Error())
+	}
+}
+
+// Completes the command name, or its first argument once the name is typed.
+func (v *GameView) completeCommand(text string) []string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || (len(fields) == 1 && !strings.HasSuffix(text, " ")) {
+		prefix := ""
+		if len(fields) == 1 {
+			prefix = fields[0]
+		}
+		candidates := make([]string, 0)
+		for _, command := range v.matchCommands(prefix) {
+			candidates = append(candidates, command.name)
+		}
+		return candidates
+	}
+
+	matches := v.matchCommands(fields[0])
+	if len(matches) != 1 || matches[0].arguments == nil || len(fields) > 2 {
+		return nil
+	}
+
+	prefix := ""
+	if len(fields) == 2 {
+		prefix = fields[1]
+	}
+	candidates := make([]string, 0)
+	for _, argument := range matches[0].arguments() {
+		if strings.HasPrefix(argument, prefix) {
+			candidates = append(candidates, matches[0].name+" "+argument)
+		}
+	}
+	return candidates
+}
+
+// Finds commands starting with the prefix, an exact match wins over longer names.
+func (v *GameView) matchCommands(prefix string) []GameCommand {
+	matches := make([]GameCommand, 0)
+	for _, command := range v.commands() {
+		if command.name == prefix {
+			return []GameCommand{command}
+		}
+		if strings.HasPrefix(command.name, prefix) {
+			matches = append(matches, command)
+		}
+	}
+	return matches
+}
+
+// Makes a command, which moves the cursor to the cell given as arguments and then acts there.
+func (v *GameView) atLocation(act func()) func(args []string) error {
+	return func(args []string) error {
+		x, y, err := v.parseLocation(args)
+		if err != nil {
+			return err
+		}
+
+		v.cx, v.cy = x, y
+		act()
+		return nil
+	}
+}
+
+// Parses 1-based column and row into cell coordinates.
+func (v *GameView) parseLocation(fields []string) (x, y int, err error) {
+	if len(fields) != 2 {
+		return 0, 0, errors.New("enter column and row separated by a space")
+	}
+
+	x, errX := strconv.Atoi(fields[0])
+	y, errY := strconv.Atoi(fields[1])
+	if errX != nil || errY != nil || v.game.IsOutOfBounds(x-1, y-1) {
+		return 0, 0, fmt.Errorf("column must be from 1 to %d, row from 1 to %d", v.game.Width(), v.game.Height())
+	}
+
+	return x - 1, y - 1, nil
+}
+
+// Moves the cursor to the closest unrevealed cell, which is certainly safe according to the numbers on the board.
+func (v *GameView) hint() error {
+	if v.game.Status() != game.StatusStarted {
+		return errors.New("only a game in progress has hints")
+	}
+
+	safe := v.game.Solve().SafeLocations()
+	if len(safe) == 0 {
+		return errors.New("no cell is proven safe, a guess is needed")
+	}
+
+	width := v.game.Width()
+	distance := func(location int) int {
+		x, y := location%width, location/width
+		return max(x-v.cx, v.cx-x, y-v.cy, v.cy-y)
+	}
+	closest := slices.MinFunc(safe, func(a, b int) int { return distance(a) - distance(b) })
+	v.cx, v.cy = closest%width, closest/width
+	v.setNotice(fmt.Sprintf("Safe: column %d, row %d", v.cx+1, v.cy+1))
+	return nil
+}
+
+// Takes back the last moves, keeping the cursor in place.
+func (v *GameView) undo(moves int) error {
+	g, ok := v.game.Undo(moves)
+	if !ok {
+		return errors.New("nothing to undo")
+	}
+
+	cx, cy := v.cx, v.cy
+	v.setGame(g)
+	v.cx, v.cy = cx, cy
+	return nil
+}
+
+// Returns path of a named save slot.
+func saveSlotPath(slot string) string {
+	return game.DataPath(path.Join("saves", slot+".json"))
+}
+
+// Lists names of existing save slots.
+func saveSlots() []string {
+	entries, _ := os.ReadDir(path.Dir(saveSlotPath("slot")))
+	slots := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if slot, ok := strings.CutSuffix(entry.Name(), ".json"); ok && !entry.IsDir() {
+			slots = append(slots, slot)
+		}
+	}
+	return slots
+}
+
+// Checks that a single slot name is given, which is safe to use as a file name.
+func parseSaveSlot(args []string) (string, error) {
+	if len(args) != 1 || !saveSlotPattern.MatchString(args[0]) {
+		return "", errors.New("enter a slot name of letters, digits, - and _")
+	}
+	return args[0], nil
+}
+
+// Writes the game into a named save slot.
+func saveToSlot(g *game.Game, slot string) error {
+	filePath := saveSlotPath(slot)
+	if err := os.MkdirAll(path.Dir(filePath), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, g.Save().Encode(), 0600)
+}
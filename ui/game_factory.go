@@ -4,6 +4,17 @@ import (
 	"github.com/borogk/hsweeper/game"
 )
 
+// All built-in game modes in the order of the title menu.
+var builtInModes = []string{game.ModeExpert, game.ModeBig, game.ModeClassicEasy, game.ModeClassicMedium, game.ModeClassicExpert}
+
+// Rules of built-in modes with a fixed size, H-Big is sized by the screen.
+var builtInRules = map[string]game.Rules{
+	game.ModeExpert:        {Mode: game.ModeExpert, Width: 30, Height: 16, Mines: 99, Hearts: 1, Lives: 1},
+	game.ModeClassicEasy:   {Mode: game.ModeClassicEasy, Width: 9, Height: 9, Mines: 10, Lives: 1},
+	game.ModeClassicMedium: {Mode: game.ModeClassicMedium, Width: 16, Height: 16, Mines: 40, Lives: 1},
+	game.ModeClassicExpert: {Mode: game.ModeClassicExpert, Width: 30, Height: 16, Mines: 99, Lives: 1},
+}
+
 // GameFactory repeatedly creates new game instances to facilitate restarts.
 // May return nil, which means restarting the game is impossible.
 type GameFactory func() *game.Game
@@ -21,18 +32,28 @@ func newExistingGameFactory(g *game.Game) GameFactory {
 	}
 }
 
-// H-Expert game factory.
-func newExpertGameFactory() GameFactory {
+// Code game factory, always restarts the same board encoded in a shareable code.
+func newCodeGameFactory(code string) GameFactory {
 	return func() *game.Game {
-		return game.NewGame(30, 16, 99, 1, 1)
+		g, err := game.DecodeCode(code)
+		if err != nil {
+			return nil
+		}
+
+		return g
 	}
 }
 
+// Factory of a built-in mode with a fixed size.
+func newBuiltInGameFactory(mode string) GameFactory {
+	return newCustomGameFactory(builtInRules[mode])
+}
+
 // H-Big game factory.
-func (v *TitleMenuView) newBigGameFactory() GameFactory {
+func newBigGameFactory(ui *Ui) GameFactory {
 	return func() *game.Game {
-		width, height := v.ui.screen.Size()
-		gameWidth := (width - 2) / 3
+		width, height := ui.screen.Size()
+		gameWidth := (width - 2) / ui.settings.Density.cellWidth()
 		gameHeight := height - 5
 		if gameWidth < 30 {
 			gameWidth = 30
@@ -41,20 +62,48 @@ func (v *TitleMenuView) newBigGameFactory() GameFactory {
 			gameHeight = 16
 		}
 
-		cells := gameWidth * gameHeight
-		mines := cells / 5
-		if mines < 99 {
-			mines = 99
-		}
-		hearts := cells/480 - cells/2400
-		extraLives := cells / 2400
-		return game.NewGame(gameWidth, gameHeight, mines, hearts, 1+extraLives)
+		return game.NewGameWithRules(BigRules(gameWidth, gameHeight))
+	}
+}
+
+// BigRules returns rules of H-Big for the board size. Mines, hearts and extra lives grow with the amount of cells.
+func BigRules(width, height int) game.Rules {
+	cells := width * height
+	mines := cells / 5
+	if mines < 99 {
+		mines = 99
+	}
+	hearts := cells/480 - cells/2400
+	extraLives := cells / 2400
+	return game.Rules{
+		Mode:   game.ModeBig,
+		Width:  width,
+		Height: height,
+		Mines:  mines,
+		Hearts: hearts,
+		Lives:  1 + extraLives,
+	}
+}
+
+// Custom game factory, for rules set up in the custom game dialog or saved as a preset.
+func newCustomGameFactory(rules game.Rules) GameFactory {
+	return func() *game.Game {
+		return game.NewGameWithRules(rules)
 	}
 }
 
-// Classic game factory.
-func newClassicGameFactory(width, height, mines int) GameFactory {
+// Seeded game factory, the first game gets the seed and the rest are random. As the layout may be known in advance,
+// the seeded game counts as a retry.
+func newSeededGameFactory(gameFactory GameFactory, seed int64) GameFactory {
+	once := true
 	return func() *game.Game {
-		return game.NewGame(width, height, mines, 0, 1)
+		g := gameFactory()
+		if once && g != nil {
+			once = false
+			g = game.NewSeededGame(g.Rules(), seed)
+			g.MarkRetry()
+		}
+
+		return g
 	}
 }
@@ -1,9 +1,12 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
 
 	"github.com/borogk/hsweeper/game"
 	"github.com/gdamore/tcell/v2"
@@ -20,142 +23,245 @@ type (
 
 	// GameView is responsible for gameplay input and graphics.
 	GameView struct {
-		ui           *Ui
-		gameFactory  GameFactory
-		game         *game.Game
-		autoSaver    *game.AutoSaver
-		savePath     string
-		cx           int
-		cy           int
-		effects      []*Effect
-		effectsMutex sync.Mutex
+		ui               *Ui
+		gameFactory      GameFactory
+		game             *game.Game
+		autoSaver        *game.AutoSaver
+		savePath         string
+		statsPath        string
+		leaderboardPath  string
+		achievementsPath string
+		record           *game.GameRecord
+		notice           string
+		noticeUntil      time.Time
+		cx               int
+		cy               int
+		viewport         Viewport
+		showMinimap      bool
+		count            int
+		effects          []*Effect
+		effectsMutex     sync.Mutex
 	}
 )
 
-func newGameView(ui *Ui, gameFactory GameFactory, savePath string) *GameView {
+// How long notifications stay on screen.
+const noticeDuration = 5 * time.Second
+
+// Largest count typed before a move, enough to cross any board.
+const maxMoveCount = 999
+
+func newGameView(ui *Ui, gameFactory GameFactory, savePath, statsPath, leaderboardPath, achievementsPath string) *GameView {
 	view := &GameView{
-		ui:          ui,
-		gameFactory: gameFactory,
-		savePath:    savePath,
-		effects:     make([]*Effect, 0),
+		ui:               ui,
+		gameFactory:      gameFactory,
+		savePath:         savePath,
+		statsPath:        statsPath,
+		leaderboardPath:  leaderboardPath,
+		achievementsPath: achievementsPath,
+		effects:          make([]*Effect, 0),
 	}
 	view.startGame()
 	return view
 }
 
 func (v *GameView) OnActivate() {
-
+	// Notifications pending behind other views are shown when getting back
+	if v.notice != "" && v.noticeUntil.IsZero() {
+		v.showNotice()
+	}
 }
 
 func (v *GameView) OnDeactivate() {
-	// Finalizing here makes sure the game is instantly saved on exit
+	// Finalizing here makes sure the game is instantly saved on exit, the clock must be stopped to save exact time
+	v.game.PauseClock()
 	v.autoSaver.Finalize()
 }
 
 func (v *GameView) OnInput(key tcell.Key, rune rune) {
 	gameActionDone := false
 
-	switch key {
-	case tcell.KeyLeft:
-		v.moveCursor(-1, 0)
-	case tcell.KeyRight:
-		v.moveCursor(1, 0)
-	case tcell.KeyUp:
-		v.moveCursor(0, -1)
-	case tcell.KeyDown:
-		v.moveCursor(0, 1)
-	case tcell.KeyEnter:
+	action, bound := v.ui.keys.Action(key, rune)
+
+	// Unbound digits make up a count repeating the next move, once it's started any digit continues it
+	if key == tcell.KeyRune && unicode.IsDigit(rune) && (v.count > 0 || (!bound && rune != '0')) {
+		v.count = min(v.count*10+int(rune-'0'), maxMoveCount)
+		return
+	}
+	count := max(v.count, 1)
+	if v.count > 0 {
+		v.count = 0
+		if action == ActionBack {
+			return
+		}
+	}
+
+	switch action {
+	case ActionLeft:
+		v.moveCursor(-1, 0, count)
+	case ActionRight:
+		v.moveCursor(1, 0, count)
+	case ActionUp:
+		v.moveCursor(0, -1, count)
+	case ActionDown:
+		v.moveCursor(0, 1, count)
+	case ActionUpLeft:
+		v.moveCursor(-1, -1, count)
+	case ActionUpRight:
+		v.moveCursor(1, -1, count)
+	case ActionDownLeft:
+		v.moveCursor(-1, 1, count)
+	case ActionDownRight:
+		v.moveCursor(1, 1, count)
+	case ActionPageUp:
+		v.moveCursorClamped(0, -v.viewport.height*count)
+	case ActionPageDown:
+		v.moveCursorClamped(0, v.viewport.height*count)
+	case ActionPageLeft:
+		v.moveCursorClamped(-v.viewport.width*count, 0)
+	case ActionPageRight:
+		v.moveCursorClamped(v.viewport.width*count, 0)
+	case ActionEdgeUp:
+		v.moveCursorClamped(0, -v.game.Height())
+	case ActionEdgeDown:
+		v.moveCursorClamped(0, v.game.Height())
+	case ActionEdgeLeft:
+		v.moveCursorClamped(-v.game.Width(), 0)
+	case ActionEdgeRight:
+		v.moveCursorClamped(v.game.Width(), 0)
+	case ActionNextCell:
+		v.jump(1, count, v.isUnrevealed, "No unrevealed cells")
+	case ActionPrevCell:
+		v.jump(-1, count, v.isUnrevealed, "No unrevealed cells")
+	case ActionNextNum:
+		v.jump(1, count, v.isUnresolvedNumber, "No unresolved numbers")
+	case ActionPrevNum:
+		v.jump(-1, count, v.isUnresolvedNumber, "No unresolved numbers")
+	case ActionHeart:
+		v.jumpToNearestHeart()
+	case ActionGoTo:
+		v.promptGoTo()
+	case ActionMinimap:
+		v.showMinimap = !v.showMinimap
+		v.ui.fullRefresh()
+	case ActionDensity:
+		v.ui.settings.Density = v.ui.settings.Density.next()
+		v.ui.saveSettings()
+		v.setNotice("Density: " + Densities[v.ui.settings.Density].Name)
+		v.ui.fullRefresh()
+	case ActionPrimary:
 		gameActionDone = v.actionButton()
-	case tcell.KeyEscape:
-		v.ui.popView()
-	case tcell.KeyDelete, tcell.KeyBackspace:
+	case ActionBack:
+		v.quit()
+	case ActionClear:
 		v.game.ClearFlagAndQuestion(v.cx, v.cy)
 		gameActionDone = true
-	default:
-		switch rune {
-		case ' ':
-			gameActionDone = v.actionButton()
-		case 'r':
-			revealResult := v.game.Reveal(v.cx, v.cy)
+	case ActionReveal:
+		v.forceReveal()
+	case ActionFlag:
+		v.game.ToggleFlag(v.cx, v.cy)
+		gameActionDone = true
+	case ActionQuestion:
+		if v.ui.settings.QuestionMarks {
+			v.game.ToggleQuestion(v.cx, v.cy)
 			gameActionDone = true
-			if revealResult == game.RevealResultBlast {
-				v.startBlastFlashEffect()
-			}
-		case 'f':
-			v.game.ToggleFlag(v.cx, v.cy)
+		}
+	case ActionExport:
+		v.ui.pushView(newCodeView(v.ui, v.game))
+	case ActionCommand:
+		v.openCommandLine()
+	case ActionRetry:
+		v.retry()
+	case ActionPause:
+		v.Pause()
+	case ActionHelp:
+		v.ui.pushView(newHelpView(v.ui, v.game.Rules()))
+	}
+
+	if gameActionDone {
+		v.onGameAction()
+	}
+}
+
+// Pause hides the board and stops the clock until any key is pressed, only a game in progress is paused.
+func (v *GameView) Pause() {
+	if v.game.Status() == game.StatusStarted {
+		v.ui.pushView(newPauseView(v.ui, v.game, v.autoSaver))
+	}
+}
+
+// Left click reveals or acts like the action button, right click flags, middle or left+right click reveals around.
+func (v *GameView) OnClick(x, y int, buttons tcell.ButtonMask) {
+	cellX, cellY, ok := boardCellAt(v.ui.screen, v.game, v.viewport, x, y)
+	if !ok {
+		return
+	}
+
+	// Clicking a finished field restarts, just like the action key
+	if v.game.IsFinished() {
+		if buttons == tcell.ButtonPrimary {
+			v.actionButton()
+		}
+		return
+	}
+	v.cx, v.cy = cellX, cellY
+
+	gameActionDone := false
+	switch buttons {
+	case tcell.ButtonPrimary:
+		if cell := v.game.Cell(v.cx, v.cy); cell.IsRevealed() {
+			gameActionDone = v.actionButton()
+		} else if !cell.IsFlagged() && !cell.IsQuestioned() {
+			v.reveal()
 			gameActionDone = true
-		case 'q':
-			v.game.ToggleQuestion(v.cx, v.cy)
+		}
+	case tcell.ButtonSecondary:
+		v.game.ToggleFlag(v.cx, v.cy)
+		gameActionDone = true
+	default:
+		if v.game.Cell(v.cx, v.cy).IsRevealed() {
+			v.advancedReveal()
 			gameActionDone = true
 		}
 	}
 
-	// Instruct auto-saver to update the save file only after the game advances.
 	if gameActionDone {
-		v.autoSaver.DeferSave()
+		v.onGameAction()
 	}
 }
 
 func (v *GameView) ContentSize() (width, height int) {
-	return v.game.Width()*3 + 2, v.game.Height() + 4
+	screenWidth, screenHeight := v.ui.screen.Size()
+	return boardSize(fitViewport(v.game, screenWidth, screenHeight, v.ui.settings.Density, v.ui.settings.Rulers, v.showMinimap, v.viewport))
 }
 
 func (v *GameView) Draw(screen tcell.Screen) {
 	screenWidth, screenHeight := screen.Size()
-	contentWidth, contentHeight := v.ContentSize()
 	palette := gamePalette(v.game)
+	v.viewport = fitViewport(v.game, screenWidth, screenHeight, v.ui.settings.Density, v.ui.settings.Rulers, v.showMinimap, v.viewport).follow(v.cx, v.cy)
 
-	offsetX := (screenWidth - contentWidth) / 2
-	offsetY := (screenHeight - contentHeight) / 2
-
-	// Status message on top of the game field
-	statusMessage, statusStyle, statusCentered := v.statusAppearance(palette)
-	statusX := offsetX + 1
-	statusY := offsetY + 1
-	if statusCentered {
-		statusX += (v.game.Width()*3 - len(statusMessage)) / 2
-	}
-	screen.PutStrStyled(0, statusY, fmt.Sprintf("%*s", screenWidth, ""), palette.Blank)
-	screen.PutStrStyled(statusX, statusY, statusMessage, statusStyle)
-
-	// Game field border
-	borderLeft := offsetX
-	borderRight := borderLeft + v.game.Width()*3 + 1
-	borderTop := statusY + 1
-	borderBottom := borderTop + v.game.Height() + 1
-	screen.Put(borderLeft, borderTop, "┌", palette.Border)
-	screen.Put(borderRight, borderTop, "┐", palette.Border)
-	screen.Put(borderLeft, borderBottom, "└", palette.Border)
-	screen.Put(borderRight, borderBottom, "┘", palette.Border)
-	for x := borderLeft + 1; x < borderRight; x++ {
-		screen.Put(x, borderTop, "─", palette.Border)
-		screen.Put(x, borderBottom, "─", palette.Border)
-	}
-	for y := borderTop + 1; y < borderBottom; y++ {
-		screen.Put(borderLeft, y, "│", palette.Border)
-		screen.Put(borderRight, y, "│", palette.Border)
-	}
-
-	printCell := func(x, y int, symbol string, style tcell.Style) {
-		cellX := borderLeft + 1 + x*3
-		cellY := borderTop + 1 + y
-		screen.PutStrStyled(cellX, cellY, symbol, style)
-	}
-
-	// Game cells
-	for x := 0; x < v.game.Width(); x++ {
-		for y := 0; y < v.game.Height(); y++ {
-			symbol, style := v.cellAppearance(x, y, palette)
-			printCell(x, y, symbol, style)
-		}
+	cursorX, cursorY := v.cx, v.cy
+	if v.game.IsFinished() {
+		cursorX, cursorY = -1, -1
 	}
+	status := v.statusAppearance(palette)
+	printCell := drawBoard(screen, v.game, v.viewport, palette, v.ui.settings.Assist, status, cursorX, cursorY)
+
+	notice, noticeStyle := "", palette.WinText
+	if v.count > 0 {
+		notice, noticeStyle = fmt.Sprintf("Count: %d", v.count), palette.PlainText
+	} else if time.Now().Before(v.noticeUntil) {
+		notice = v.notice
+	}
+	drawBoardNotice(screen, v.viewport, palette, notice, noticeStyle)
+
+	drawMinimap(screen, v.game, v.viewport, palette)
 
 	// Display effects and remove any that have expired
 	v.effectsMutex.Lock()
 	validEffects := make([]*Effect, 0)
 	for _, effect := range v.effects {
 		if !effect.expired {
-			symbol, _ := v.cellAppearance(effect.x, effect.y, palette)
+			symbol, _ := cellAppearance(v.game, effect.x, effect.y, palette)
 			printCell(effect.x, effect.y, symbol, effect.style)
 			validEffects = append(validEffects, effect)
 		}
@@ -164,99 +270,313 @@ func (v *GameView) Draw(screen tcell.Screen) {
 	v.effectsMutex.Unlock()
 }
 
+// Instructs auto-saver to update the save file only after the game advances, records the game once it's finished.
+func (v *GameView) onGameAction() {
+	v.autoSaver.DeferSave()
+	v.recordFinishedGame()
+}
+
 // Tries to start a new game from gameFactory. Exits the game view if the factory returns nil.
 func (v *GameView) startGame() {
-	g := v.gameFactory()
-	if g != nil {
-		v.game = g
-		v.cx = g.Width() / 2
-		v.cy = g.Height() / 2
-
-		if v.autoSaver != nil {
-			v.autoSaver.Finalize()
-		}
-		v.autoSaver = game.NewAutoSaver(g, v.savePath)
+	if g := v.gameFactory(); g != nil {
+		v.setGame(g)
 	} else {
 		v.ui.popView()
 	}
 }
 
-func (v *GameView) statusAppearance(palette Palette) (message string, style tcell.Style, centered bool) {
-	switch v.game.Status() {
-	case game.StatusReady:
-		return "Ready?", palette.ReadyText, true
-	case game.StatusStarted:
-		const maxLives = 6
-		livesString := ""
-		livesPadding := maxLives*2 + 3
-		for i := 0; i < v.game.LivesRemaining() && i < maxLives; i++ {
-			livesString += "♥ "
+// Restarts a lost game on the same layout from the same first reveal, putting the cursor there.
+func (v *GameView) retry() {
+	if v.game.Status() != game.StatusLost {
+		return
+	}
+
+	if g, ok := v.game.Retry(); ok {
+		v.setGame(g)
+		v.cx, v.cy, _ = g.StartLocation()
+	}
+}
+
+// Puts the game into play, saving it from now on.
+func (v *GameView) setGame(g *game.Game) {
+	v.game = g
+	v.record = nil
+	v.notice = ""
+	v.noticeUntil = time.Time{}
+	v.count = 0
+	v.cx = g.Width() / 2
+	v.cy = g.Height() / 2
+	screenWidth, screenHeight := v.ui.screen.Size()
+	viewport := fitViewport(g, screenWidth, screenHeight, v.ui.settings.Density, v.ui.settings.Rulers, v.showMinimap, Viewport{})
+	v.viewport = fitViewport(g, screenWidth, screenHeight, v.ui.settings.Density, v.ui.settings.Rulers, v.showMinimap, viewport.centered(v.cx, v.cy))
+	g.ResumeClock()
+
+	if v.autoSaver != nil {
+		v.autoSaver.Finalize()
+	}
+	v.autoSaver = game.NewAutoSaver(g, v.savePath, v.ui.settings.AutosaveEvery())
+}
+
+// Measures the game and adds it to statistics as soon as it's finished, but only once.
+// Only games with full history are added, as the rest can't be measured reliably.
+// The summary goes first, so any prompts for records are shown on top of it, notifications wait until it's closed.
+func (v *GameView) recordFinishedGame() {
+	if v.game.IsFinished() && v.record == nil {
+		record := game.NewGameRecord(v.game)
+		v.record = &record
+		v.ui.pushView(newSummaryView(v.ui, v.game, v.retry))
+
+		if v.game.HasFullHistory() {
+			// Failing to record is not a reason to interrupt the game
+			stats, _ := game.RecordGame(v.statsPath, record)
+			v.unlockAchievements(stats)
+
+			leaderboard := game.LoadLeaderboard(v.leaderboardPath)
+			if leaderboard.Qualifies(record) {
+				v.promptLeaderboardName(leaderboard, record)
+			}
+		}
+	}
+}
+
+// Unlocks achievements reached by the finished game and prepares a notification about them.
+func (v *GameView) unlockAchievements(stats *game.Stats) {
+	achievements := game.LoadAchievements(v.achievementsPath)
+	unlocked := achievements.Update(stats)
+	if len(unlocked) == 0 {
+		return
+	}
+	_ = achievements.Save(v.achievementsPath)
+
+	names := make([]string, 0, len(unlocked))
+	for _, achievement := range unlocked {
+		names = append(names, achievement.Name)
+	}
+	v.notice = glyphs.Achievement + " Achievement unlocked: " + strings.Join(names, ", ")
+}
+
+// Shows the message above the game field for a while.
+func (v *GameView) setNotice(message string) {
+	v.notice = message
+	v.showNotice()
+}
+
+// Shows the notification above the game field for a while.
+func (v *GameView) showNotice() {
+	v.noticeUntil = time.Now().Add(noticeDuration)
+	time.AfterFunc(noticeDuration, func() {
+		v.ui.post(v.ui.refresh)
+	})
+}
+
+// Asks for a name to put a qualifying win into the leaderboard, then shows where it landed.
+func (v *GameView) promptLeaderboardName(leaderboard *game.Leaderboard, record game.GameRecord) {
+	title := "New record! Enter your name (ESC to skip):"
+	v.ui.pushView(newPromptView(v.ui, title, leaderboard.LastName, func(name string) error {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return errors.New("name can't be empty")
 		}
-		if v.game.LivesRemaining() > maxLives {
-			livesString += fmt.Sprintf("+%-2d", v.game.LivesRemaining()-maxLives)
+		if len([]rune(name)) > maxPlayerNameWidth {
+			return fmt.Errorf("name can't be longer than %d symbols", maxPlayerNameWidth)
 		}
 
-		return fmt.Sprintf(
-			"%-*s%-*smines:%4d",
-			livesPadding,
-			livesString,
-			v.game.Width()*3-livesPadding-10,
-			"",
-			v.game.MinesRemaining(),
-		), palette.StatusText, false
+		rank := leaderboard.Add(name, record)
+		_ = leaderboard.Save(v.leaderboardPath)
+		v.ui.popView()
+		v.ui.pushView(newLeaderboardView(v.ui, v.leaderboardPath, record.Rules.Name(), rank))
+		return nil
+	}))
+}
+
+func (v *GameView) statusAppearance(palette Palette) BoardStatus {
+	switch v.game.Status() {
+	case game.StatusReady:
+		return BoardStatus{"Ready?", palette.ReadyText, true}
+	case game.StatusStarted:
+		return BoardStatus{v.livesAndMines(), palette.StatusText, false}
 	case game.StatusLost:
-		return "GAME OVER", palette.LoseText, true
+		return BoardStatus{"GAME OVER", palette.LoseText, true}
 	case game.StatusWon:
-		return "Well done!", palette.WinText, true
+		return BoardStatus{v.winMessage(), palette.WinText, true}
 	default:
 		panic("Unknown game status")
 	}
 }
 
-func (v *GameView) cellAppearance(x, y int, palette Palette) (symbol string, style tcell.Style) {
-	symbol = "   "
-	style = palette.Blank
+// Shows lives on the left and mines on the right of the field. Narrow fields get a short form with counts only,
+// the narrowest ones only the mines.
+func (v *GameView) livesAndMines() string {
+	const maxLives = 6
+	width := v.viewport.fieldWidth()
+	lives := v.game.LivesRemaining()
+	mines := v.game.MinesRemaining()
+
+	livesString := ""
+	livesPadding := maxLives*2 + 3
+	for i := 0; i < lives && i < maxLives; i++ {
+		livesString += glyphs.Heart + " "
+	}
+	if lives > maxLives {
+		livesString += fmt.Sprintf("+%-2d", lives-maxLives)
+	}
+	minesString := fmt.Sprintf("mines:%4d", mines)
+	if livesPadding+len(minesString) > width {
+		livesString = fmt.Sprintf("%s%d", glyphs.Heart, lives)
+		livesPadding = len([]rune(livesString)) + 1
+		minesString = fmt.Sprintf("%s%d", glyphs.Mine, mines)
+	}
+	if livesPadding+len(minesString) > width {
+		livesString, livesPadding = "", 0
+	}
 
-	cell := v.game.Cell(x, y)
-	if cell.IsRevealed() {
-		if cell.IsMine() {
-			symbol = " * "
-			style = palette.RevealedMine
-		} else if cell.AdjacentMines() > 0 {
-			symbol = fmt.Sprintf(" %c ", rune('0'+cell.AdjacentMines()))
-			style = palette.Numbers[cell.AdjacentMines()%len(palette.Numbers)]
-		} else if cell.IsHeart() {
-			symbol = " ♥ "
-			style = palette.Heart
-		}
-	} else if cell.IsQuestioned() {
-		symbol = " ? "
-		style = palette.Question
-	} else if cell.IsFlagged() {
-		symbol = " ⚑ "
-		style = palette.Flag
-	} else if cell.IsMine() && v.game.IsFinished() {
-		symbol = " * "
-		style = palette.UnrevealedMine
-	} else {
-		symbol = " ■ "
-		style = palette.Unrevealed
+	message := []rune(fmt.Sprintf("%-*s%*s", livesPadding, livesString, width-livesPadding, minesString))
+	return string(message[:min(len(message), width)])
+}
+
+// Shows off speedrun metrics after a win, as many as fit the game width.
+func (v *GameView) winMessage() string {
+	message := "Well done!"
+	if v.record == nil {
+		return message
 	}
 
-	if !v.game.IsFinished() && x == v.cx && y == v.cy {
-		style = palette.Cursor
+	metrics := v.record.Metrics
+	for _, part := range []string{
+		formatDuration(metrics.Time),
+		fmt.Sprintf("3BV/s %.2f", metrics.ThreeBVPerSecond()),
+		fmt.Sprintf("3BV %d", metrics.ThreeBV),
+		fmt.Sprintf("IOE %.2f", metrics.Efficiency()),
+		fmt.Sprintf("ZiNi %d", metrics.ZiNi),
+		fmt.Sprintf("openings %d", metrics.Openings),
+		fmt.Sprintf("islands %d", metrics.Islands),
+	} {
+		if len(message)+len(part)+3 > v.viewport.fieldWidth() {
+			break
+		}
+		message += "   " + part
 	}
 
-	return
+	return message
 }
 
-func (v *GameView) moveCursor(dx, dy int) {
-	if !v.game.IsFinished() && !v.game.IsOutOfBounds(v.cx+dx, v.cy+dy) {
+// Moves the cursor by count steps, stopping at the edges.
+func (v *GameView) moveCursor(dx, dy, count int) {
+	for range count {
+		if v.game.IsFinished() || v.game.IsOutOfBounds(v.cx+dx, v.cy+dy) {
+			return
+		}
 		v.cx = v.cx + dx
 		v.cy = v.cy + dy
 	}
 }
 
+// Moves the cursor as far as possible, stopping at the edges.
+func (v *GameView) moveCursorClamped(dx, dy int) {
+	if !v.game.IsFinished() {
+		v.cx = max(min(v.cx+dx, v.game.Width()-1), 0)
+		v.cy = max(min(v.cy+dy, v.game.Height()-1), 0)
+	}
+}
+
+// Moves the cursor to the count-th cell matching the condition, searching in reading order forward or backward
+// and wrapping around the board. Shows the notice and stays in place if there are no such cells.
+func (v *GameView) jump(step, count int, matches func(x, y int) bool, notFound string) {
+	if v.game.IsFinished() {
+		return
+	}
+
+	width := v.game.Width()
+	size := width * v.game.Height()
+	location := v.cx + v.cy*width
+	for range count {
+		found := false
+		for i := 1; i <= size && !found; i++ {
+			next := ((location+step*i)%size + size) % size
+			if matches(next%width, next/width) {
+				location = next
+				found = true
+			}
+		}
+		if !found {
+			v.setNotice(notFound)
+			return
+		}
+	}
+
+	v.cx, v.cy = location%width, location/width
+}
+
+// Checks if the cell is still to be decided, flagged cells are considered decided.
+func (v *GameView) isUnrevealed(x, y int) bool {
+	cell := v.game.Cell(x, y)
+	return !cell.IsRevealed() && !cell.IsFlagged()
+}
+
+// Checks if the cell is a revealed number with undecided cells around.
+func (v *GameView) isUnresolvedNumber(x, y int) bool {
+	cell := v.game.Cell(x, y)
+	if !cell.IsRevealed() || cell.IsMine() || cell.AdjacentMines() == 0 {
+		return false
+	}
+
+	for dx := -1; dx <= 1; dx++ {
+		for dy := -1; dy <= 1; dy++ {
+			if !v.game.IsOutOfBounds(x+dx, y+dy) && v.isUnrevealed(x+dx, y+dy) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Moves the cursor to the closest revealed heart not picked up yet, counting diagonal steps as one.
+func (v *GameView) jumpToNearestHeart() {
+	if v.game.IsFinished() {
+		return
+	}
+
+	bestX, bestY, bestDistance := -1, -1, 0
+	for y := 0; y < v.game.Height(); y++ {
+		for x := 0; x < v.game.Width(); x++ {
+			cell := v.game.Cell(x, y)
+			if !cell.IsRevealed() || !cell.IsHeart() {
+				continue
+			}
+
+			distance := max(x-v.cx, v.cx-x, y-v.cy, v.cy-y)
+			if bestX < 0 || distance < bestDistance {
+				bestX, bestY, bestDistance = x, y, distance
+			}
+		}
+	}
+
+	if bestX < 0 {
+		v.setNotice("No hearts to pick up")
+		return
+	}
+	v.cx, v.cy = bestX, bestY
+}
+
+// Asks for 1-based column and row, as shown by the rulers, and moves the cursor there.
+func (v *GameView) promptGoTo() {
+	if v.game.IsFinished() {
+		return
+	}
+
+	title := "Go to column and row, e.g. 12 5 (ESC to cancel):"
+	v.ui.pushView(newPromptView(v.ui, title, "", func(text string) error {
+		x, y, err := v.parseLocation(strings.FieldsFunc(text, func(r rune) bool { return r == ' ' || r == ',' }))
+		if err != nil {
+			return err
+		}
+
+		v.cx, v.cy = x, y
+		v.ui.popView()
+		return nil
+	}))
+}
+
 // Processes context-sensitive action button, returns true if the action resulted in the game advancing.
 func (v *GameView) actionButton() bool {
 	cell := v.game.Cell(v.cx, v.cy)
@@ -268,12 +588,7 @@ func (v *GameView) actionButton() bool {
 			v.game.ToggleFlag(v.cx, v.cy)
 			return true
 		} else if cell.AdjacentMines() > 0 {
-			revealResult := v.game.AdvancedReveal(v.cx, v.cy)
-			if revealResult != game.RevealResultBlast {
-				v.startRevealFlashEffect()
-			} else {
-				v.startBlastFlashEffect()
-			}
+			v.advancedReveal()
 			return true
 		} else if cell.IsHeart() {
 			v.game.Pickup(v.cx, v.cy)
@@ -286,6 +601,64 @@ func (v *GameView) actionButton() bool {
 	return false
 }
 
+// Leaves to the title menu, asking first if the game is in progress. The game stays saved either way.
+func (v *GameView) quit() {
+	if v.game.Status() != game.StatusStarted {
+		v.ui.popView()
+		return
+	}
+
+	v.ui.confirm(v.ui.popView, "Quit to menu?", "The game is saved and can be continued later.")
+}
+
+// Replaces the game with another one, asking first if the game is in progress.
+func (v *GameView) replaceGame(replace func(), question string) {
+	if v.game.Status() != game.StatusStarted {
+		replace()
+		return
+	}
+
+	v.ui.confirm(replace, question, "The current game will be lost.")
+}
+
+// Reveals the cell under the cursor. A cell not proven safe is revealed only after a confirmation, if it's enabled.
+func (v *GameView) forceReveal() {
+	reveal := func() {
+		v.reveal()
+		v.onGameAction()
+	}
+
+	cell := v.game.Cell(v.cx, v.cy)
+	if !v.ui.settings.ConfirmGuesses || v.game.Status() != game.StatusStarted ||
+		cell.IsRevealed() || cell.IsFlagged() || cell.IsQuestioned() {
+		reveal()
+		return
+	}
+
+	switch v.game.Solve().Knowledge(v.cx, v.cy) {
+	case game.KnowledgeSafe:
+		reveal()
+	case game.KnowledgeMine:
+		v.ui.pushView(newConfirmView(v.ui, reveal, "This cell is certainly a mine.", "Reveal anyway?"))
+	default:
+		v.ui.pushView(newConfirmView(v.ui, reveal, "This cell is not proven safe.", "Reveal anyway?"))
+	}
+}
+
+func (v *GameView) reveal() {
+	if v.game.Reveal(v.cx, v.cy) == game.RevealResultBlast {
+		v.startBlastFlashEffect()
+	}
+}
+
+func (v *GameView) advancedReveal() {
+	if v.game.AdvancedReveal(v.cx, v.cy) != game.RevealResultBlast {
+		v.startRevealFlashEffect()
+	} else {
+		v.startBlastFlashEffect()
+	}
+}
+
 func (v *GameView) startRevealFlashEffect() {
 	palette := gamePalette(v.game)
 	duration := 300 * time.Millisecond
@@ -339,6 +712,10 @@ func (v *GameView) outerFlashEffects(style tcell.Style) []*Effect {
 }
 
 func (v *GameView) startEffects(expireAfter time.Duration, effects []*Effect, filter func(int, int) bool) {
+	if !v.ui.settings.Animations {
+		return
+	}
+
 	v.effectsMutex.Lock()
 	for _, effect := range effects {
 		if filter(effect.x, effect.y) && !v.game.IsOutOfBounds(effect.x, effect.y) {
@@ -348,11 +725,13 @@ func (v *GameView) startEffects(expireAfter time.Duration, effects []*Effect, fi
 	v.effectsMutex.Unlock()
 
 	time.AfterFunc(expireAfter, func() {
-		v.effectsMutex.Lock()
-		for _, effect := range effects {
-			effect.expired = true
-		}
-		v.effectsMutex.Unlock()
-		v.ui.refresh()
+		v.ui.post(func() {
+			v.effectsMutex.Lock()
+			for _, effect := range effects {
+				effect.expired = true
+			}
+			v.effectsMutex.Unlock()
+			v.ui.refresh()
+		})
 	})
 }
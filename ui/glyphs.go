@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+type (
+	// Glyphs are symbols used to draw the game. Every glyph takes exactly one screen cell.
+	Glyphs struct {
+		Unrevealed        string
+		Flag              string
+		Question          string
+		Mine              string
+		Heart             string
+		BorderTopLeft     string
+		BorderTopRight    string
+		BorderBottomLeft  string
+		BorderBottomRight string
+		BorderHorizontal  string
+		BorderVertical    string
+		MoreUp            string // Edge indicators, showing where the board continues
+		MoreDown          string
+		MoreLeft          string
+		MoreRight         string
+		Selected          string // Marks the selected line of menus and lists
+		Previous          string // Surround a value cycled with left and right keys
+		Next              string
+		Achievement       string
+		MinimapVisible    string
+		MinimapHidden     string
+		KeysUpDown        string // Key hints, not limited to one cell
+		KeysLeftRight     string
+	}
+
+	// DisplayMode defines how fancy the graphics are.
+	DisplayMode int
+)
+
+const (
+	// DisplayAuto detects limited terminals and falls back to basic display on them.
+	DisplayAuto DisplayMode = iota
+	// DisplayFull uses Unicode symbols and at least 256 colors.
+	DisplayFull
+	// DisplayBasic uses pure ASCII symbols and 16 colors.
+	DisplayBasic
+)
+
+// Colors used by basic display.
+const basicColors = 16
+
+var unicodeGlyphs = Glyphs{
+	Unrevealed:        "■",
+	Flag:              "⚑",
+	Question:          "?",
+	Mine:              "*",
+	Heart:             "♥",
+	BorderTopLeft:     "┌",
+	BorderTopRight:    "┐",
+	BorderBottomLeft:  "└",
+	BorderBottomRight: "┘",
+	BorderHorizontal:  "─",
+	BorderVertical:    "│",
+	MoreUp:            "▲",
+	MoreDown:          "▼",
+	MoreLeft:          "◀",
+	MoreRight:         "▶",
+	Selected:          "▶",
+	Previous:          "◀",
+	Next:              "▶",
+	Achievement:       "★",
+	MinimapVisible:    "█",
+	MinimapHidden:     "░",
+	KeysUpDown:        "↑↓",
+	KeysLeftRight:     "←→",
+}
+
+var asciiGlyphs = Glyphs{
+	Unrevealed:        "#",
+	Flag:              "F",
+	Question:          "?",
+	Mine:              "*",
+	Heart:             "+",
+	BorderTopLeft:     "+",
+	BorderTopRight:    "+",
+	BorderBottomLeft:  "+",
+	BorderBottomRight: "+",
+	BorderHorizontal:  "-",
+	BorderVertical:    "|",
+	MoreUp:            "^",
+	MoreDown:          "v",
+	MoreLeft:          "<",
+	MoreRight:         ">",
+	Selected:          ">",
+	Previous:          "<",
+	Next:              ">",
+	Achievement:       "*",
+	MinimapVisible:    "#",
+	MinimapHidden:     ".",
+	KeysUpDown:        "UP/DOWN",
+	KeysLeftRight:     "LEFT/RIGHT",
+}
+
+// Glyphs currently in use, replaced by basic display.
+var glyphs = unicodeGlyphs
+
+// ParseDisplayMode converts display mode name, as given in the command line.
+func ParseDisplayMode(name string) (DisplayMode, error) {
+	switch name {
+	case "auto":
+		return DisplayAuto, nil
+	case "full":
+		return DisplayFull, nil
+	case "basic":
+		return DisplayBasic, nil
+	default:
+		return DisplayAuto, fmt.Errorf("unknown display mode %q, expected auto, full or basic", name)
+	}
+}
+
+// Picks glyphs for the display mode, returns how many colors to use out of the ones supported by the terminal.
+func applyDisplayMode(mode DisplayMode, terminalColors int) (colors int) {
+	if mode == DisplayAuto {
+		mode = DisplayFull
+		if isLimitedTerminal(terminalColors) {
+			mode = DisplayBasic
+		}
+	}
+
+	if mode == DisplayBasic {
+		glyphs = asciiGlyphs
+		return min(terminalColors, basicColors)
+	}
+
+	glyphs = unicodeGlyphs
+	return max(terminalColors, 256)
+}
+
+// Detects terminals unable to show Unicode symbols or 256 colors: Linux console, old hardware terminals,
+// non-UTF-8 locales and anything reporting fewer colors.
+func isLimitedTerminal(colors int) bool {
+	term := os.Getenv("TERM")
+	if term == "linux" || term == "dumb" || strings.HasPrefix(term, "vt") {
+		return true
+	}
+
+	return !isUTF8Locale() || colors < 256
+}
+
+// Checks the locale the same way C programs do, the first variable set wins.
+func isUTF8Locale() bool {
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := strings.ToLower(os.Getenv(name)); value != "" {
+			return strings.Contains(value, "utf-8") || strings.Contains(value, "utf8")
+		}
+	}
+
+	return false
+}
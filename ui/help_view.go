@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/borogk/hsweeper/game"
+	"github.com/gdamore/tcell/v2"
+)
+
+// HelpView explains controls and rules of the current game, drawn over the game field.
+type HelpView struct {
+	Overlay
+	ui    *Ui
+	rules game.Rules
+	lines []TextLine
+}
+
+// TextLine is a line of text in overlays along with its style.
+type TextLine struct {
+	text  string
+	style func(palette Palette) tcell.Style
+}
+
+const helpWidth = 68
+
+func newHelpView(ui *Ui, rules game.Rules) *HelpView {
+	view := &HelpView{ui: ui, rules: rules}
+	view.lines = view.helpLines()
+	return view
+}
+
+func (v *HelpView) OnActivate() {
+
+}
+
+func (v *HelpView) OnDeactivate() {
+
+}
+
+func (v *HelpView) OnInput(key tcell.Key, rune rune) {
+	v.ui.popView()
+}
+
+func (v *HelpView) ContentSize() (width, height int) {
+	return helpWidth + 4, len(v.lines) + 2
+}
+
+func (v *HelpView) Draw(screen tcell.Screen) {
+	palette := defaultPalette
+	x, y := drawOverlayBox(screen, helpWidth, len(v.lines), palette)
+	for i, line := range v.lines {
+		screen.PutStrStyled(x, y+i, line.text, line.style(palette))
+	}
+}
+
+// Creates help listing commands of the command line, drawn the same way as the game help.
+func newCommandHelpView(ui *Ui, commands []GameCommand) *HelpView {
+	view := &HelpView{ui: ui}
+	add := func(style func(palette Palette) tcell.Style, text string) {
+		view.lines = append(view.lines, TextLine{text, style})
+	}
+	title := func(palette Palette) tcell.Style { return palette.ClassicGameText }
+	plain := func(palette Palette) tcell.Style { return palette.PlainText }
+	hint := func(palette Palette) tcell.Style { return palette.Border }
+
+	add(title, "Commands")
+	add(plain, "")
+	for _, command := range commands {
+		add(plain, fmt.Sprintf("%-24s %s", strings.TrimSpace(":"+command.name+" "+command.usage), command.description))
+	}
+	add(plain, "")
+	add(plain, "Columns and rows count from 1. Commands may be shortened, TAB")
+	add(plain, "completes them, UP and DOWN go through commands typed before.")
+	add(plain, "")
+	add(hint, "Press any key to get back to the game")
+	return view
+}
+
+// Composes help from active key bindings and rules of the game.
+func (v *HelpView) helpLines() []TextLine {
+	lines := make([]TextLine, 0)
+	add := func(style func(palette Palette) tcell.Style, format string, args ...any) {
+		lines = append(lines, TextLine{fmt.Sprintf(format, args...), style})
+	}
+	title := func(palette Palette) tcell.Style { return palette.ClassicGameText }
+	plain := func(palette Palette) tcell.Style { return palette.PlainText }
+	hint := func(palette Palette) tcell.Style { return palette.Border }
+
+	add(title, "Help: %s", v.rules.Name())
+	add(plain, "")
+
+	// Controls go in two columns
+	controls := [][2]string{
+		{"Move", v.moveKeys(ActionUp, ActionLeft, ActionDown, ActionRight)},
+		{"Diagonals", v.moveKeys(ActionUpLeft, ActionUpRight, ActionDownLeft, ActionDownRight)},
+		{"Move a screen", v.moveKeys(ActionPageUp, ActionPageLeft, ActionPageDown, ActionPageRight)},
+		{"Jump to edge", v.moveKeys(ActionEdgeUp, ActionEdgeLeft, ActionEdgeDown, ActionEdgeRight)},
+		{"Unrevealed", v.moveKeys(ActionNextCell, ActionPrevCell)},
+		{"Unresolved", v.moveKeys(ActionNextNum, ActionPrevNum)},
+		{"Nearest heart", v.keys(ActionHeart)},
+		{"Go to", v.keys(ActionGoTo)},
+		{"Action key", v.keys(ActionPrimary)},
+		{"Reveal", v.keys(ActionReveal)},
+		{"Flag", v.keys(ActionFlag)},
+		{"Question mark", v.keys(ActionQuestion)},
+		{"Clear mark", v.keys(ActionClear)},
+		{"Minimap", v.keys(ActionMinimap)},
+		{"Cell width", v.keys(ActionDensity)},
+		{"Export code", v.keys(ActionExport)},
+		{"Command line", v.keys(ActionCommand)},
+		{"Pause", v.keys(ActionPause)},
+		{"Retry layout", v.keys(ActionRetry)},
+		{"Quit to menu", v.keys(ActionBack)},
+	}
+	controls = slices.DeleteFunc(controls, func(control [2]string) bool { return control[1] == "" })
+	const keysWidth = helpWidth/2 - 16
+	for i := 0; i < len(controls); i += 2 {
+		line := fmt.Sprintf("%-14s %-*.*s ", controls[i][0], keysWidth, keysWidth, controls[i][1])
+		if i+1 < len(controls) {
+			line += fmt.Sprintf("%-14s %.*s", controls[i+1][0], keysWidth, controls[i+1][1])
+		}
+		add(plain, "%s", line)
+	}
+
+	add(plain, "Digits before a move repeat it, e.g. 5 and Right moves 5 cells.")
+	add(plain, "")
+	add(title, "Action key")
+	add(plain, "Starts the game, flags unrevealed cells, reveals around numbers")
+	add(plain, "with enough flags, picks up hearts, restarts a finished game.")
+
+	add(plain, "")
+	add(title, "Hearts and lives")
+	if v.rules.Lives <= 1 && v.rules.Hearts == 0 {
+		add(plain, "No extra lives in this mode, any mine ends the game.")
+	} else {
+		add(plain, "%d %s at start, shown as %s on top. Revealing a mine costs a life", v.rules.Lives, plural(v.rules.Lives, "life", "lives"), glyphs.Heart)
+		add(plain, "instead of ending the game, the mine is removed from the board.")
+		if v.rules.Hearts > 0 {
+			add(plain, "Hearts appear as the field opens up, %d in this game. Pick them up", v.rules.Hearts)
+			add(plain, "with the action key to gain extra lives.")
+		}
+	}
+
+	add(plain, "")
+	add(hint, "Press any key to get back to the game")
+	return lines
+}
+
+// Formats keys of the action, empty if there are none.
+func (v *HelpView) keys(action Action) string {
+	return formatKeyNames(v.ui.keys.Keys(action))
+}
+
+// Formats keys of four move actions, taking the first key of each, e.g. "Up Left Down Right".
+// Arrow keys are always there and don't need repeating, so other keys are preferred.
+func (v *HelpView) moveKeys(actions ...Action) string {
+	names := make([]string, 0, len(actions))
+	arrows := false
+	for _, action := range actions {
+		keys := slices.DeleteFunc(slices.Clone(v.ui.keys.Keys(action)), func(name string) bool {
+			return name == "Up" || name == "Down" || name == "Left" || name == "Right"
+		})
+		arrows = arrows || len(keys) < len(v.ui.keys.Keys(action))
+		if len(keys) > 0 {
+			names = append(names, formatKeyNames(keys[:1]))
+		}
+	}
+
+	if len(names) < len(actions) {
+		names = nil
+	}
+	if arrows {
+		names = append([]string{"Arrows"}, names...)
+	}
+	return strings.Join(names, " ")
+}
+
+// Picks singular or plural form of a word for the count.
+func plural(count int, singular, plural string) string {
+	if count == 1 {
+		return singular
+	}
+	return plural
+}
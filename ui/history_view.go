@@ -0,0 +1,278 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"unicode"
+
+	"github.com/borogk/hsweeper/game"
+	"github.com/gdamore/tcell/v2"
+)
+
+// HistoryView lists finished games, allowing to filter, sort and replay them.
+type HistoryView struct {
+	ui           *Ui
+	statsPath    string
+	records      []game.GameRecord
+	modes        []string
+	rows         []game.GameRecord
+	sortOrder    historySortOrder
+	modeFilter   int
+	resultFilter game.Status
+	cursor       int
+	scroll       int
+}
+
+type historySortOrder int
+
+const (
+	historyByDate historySortOrder = iota
+	historyByTime
+	historyByThreeBVPerSecond
+)
+
+var historySortOrderNames = []string{"newest", "fastest", "best 3BV/s"}
+
+const historyHeader = "  Date              Mode                      Result      Time   3BV  3BV/s  Lives  Replay"
+
+// Actions handled by the view, they take precedence over hotkeys.
+var historyNavigation = []Action{
+	ActionBack, ActionUp, ActionDown, ActionPageUp, ActionPageDown,
+	ActionEdgeUp, ActionEdgeDown, ActionPageLeft, ActionPageRight, ActionPrimary,
+}
+
+// Lines taken by everything except the rows themselves.
+const historyChromeHeight = 7
+
+func newHistoryView(ui *Ui, statsPath string) *HistoryView {
+	return &HistoryView{
+		ui:           ui,
+		statsPath:    statsPath,
+		resultFilter: game.StatusReady,
+	}
+}
+
+func (v *HistoryView) OnActivate() {
+	// Records are loaded once, so returning from a replay keeps the cursor in place
+	if v.records != nil {
+		return
+	}
+
+	v.records = game.LoadStats(v.statsPath).Records
+	v.modes = make([]string, 0)
+	for _, record := range v.records {
+		if mode := record.Rules.Name(); !slices.Contains(v.modes, mode) {
+			v.modes = append(v.modes, mode)
+		}
+	}
+	v.refreshRows()
+}
+
+func (v *HistoryView) OnDeactivate() {
+
+}
+
+func (v *HistoryView) OnInput(key tcell.Key, rune rune) {
+	action, _ := v.ui.keys.Action(key, rune)
+	switch action {
+	case ActionBack:
+		v.ui.popView()
+	case ActionUp:
+		v.moveCursor(-1)
+	case ActionDown:
+		v.moveCursor(1)
+	case ActionPageUp:
+		v.moveCursor(-v.pageSize())
+	case ActionPageDown:
+		v.moveCursor(v.pageSize())
+	case ActionEdgeUp, ActionPageLeft:
+		v.moveCursor(-len(v.rows))
+	case ActionEdgeDown, ActionPageRight:
+		v.moveCursor(len(v.rows))
+	case ActionPrimary:
+		v.openReplay()
+	default:
+		// Keys bound to navigation are handled above, presets reuse some of the hotkey letters
+		if key == tcell.KeyRune {
+			v.selectHotkey(unicode.ToLower(rune))
+		}
+	}
+}
+
+// Changes sorting or filters by their hotkeys.
+func (v *HistoryView) selectHotkey(hotkey rune) {
+	switch hotkey {
+	case 's':
+		v.sortOrder = (v.sortOrder + 1) % historySortOrder(len(historySortOrderNames))
+	case 'm':
+		v.modeFilter = (v.modeFilter + 1) % (len(v.modes) + 1)
+	case 'r':
+		switch v.resultFilter {
+		case game.StatusReady:
+			v.resultFilter = game.StatusWon
+		case game.StatusWon:
+			v.resultFilter = game.StatusLost
+		default:
+			v.resultFilter = game.StatusReady
+		}
+	default:
+		return
+	}
+	v.refreshRows()
+	v.ui.fullRefresh()
+}
+
+// Returns the hotkey as shown in the hint, blank if the key is bound to navigation and doesn't work.
+func (v *HistoryView) hotkeyLabel(hotkey rune) string {
+	action, _ := v.ui.keys.Action(tcell.KeyRune, hotkey)
+	if slices.Contains(historyNavigation, action) {
+		return " "
+	}
+	return string(unicode.ToUpper(hotkey))
+}
+
+func (v *HistoryView) ContentSize() (width, height int) {
+	return len(historyHeader), max(min(len(v.rows), v.pageSize()), 1) + historyChromeHeight
+}
+
+func (v *HistoryView) Draw(screen tcell.Screen) {
+	screenWidth, screenHeight := screen.Size()
+	contentWidth, contentHeight := v.ContentSize()
+	palette := defaultPalette
+
+	x := (screenWidth - contentWidth) / 2
+	y := (screenHeight - contentHeight) / 2
+
+	screen.PutStrStyled(x, y, fmt.Sprintf("History (%d/%d)", len(v.rows), len(v.records)), palette.PlainText)
+	screen.PutStrStyled(x, y+2, historyHeader, palette.Border)
+	y += 3
+
+	if len(v.rows) == 0 {
+		screen.PutStrStyled(x, y, "  No games", palette.PlainText)
+		y++
+	}
+
+	for i := v.scroll; i < len(v.rows) && i < v.scroll+v.pageSize(); i++ {
+		record := v.rows[i]
+
+		result, style := "Lost", palette.LoseText
+		if record.Result == game.StatusWon {
+			result, style = "Won", palette.WinText
+		}
+		if record.Retry {
+			result += " R"
+		}
+
+		replay := "-"
+		if record.Board != nil && record.Board.FullHistory && record.Board.Seed != 0 {
+			replay = "yes"
+		} else if record.Board != nil {
+			replay = "board"
+		}
+
+		marker := " "
+		if i == v.cursor {
+			marker = glyphs.Selected
+		}
+
+		line := fmt.Sprintf("%s %-16s  %-24.24s  %-6s %9s  %4d  %5s  %5d  %-6s",
+			marker,
+			record.FinishedAt.Local().Format("2006-01-02 15:04"),
+			record.Rules.Name(),
+			result,
+			formatDuration(record.Time),
+			record.ThreeBV,
+			formatAverage(record.ThreeBVPerSecond(), 2, record.Result == game.StatusWon),
+			record.LivesUsed,
+			replay,
+		)
+		screen.PutStrStyled(x, y, line, style)
+		y++
+	}
+
+	mode := "all"
+	if v.modeFilter > 0 {
+		mode = v.modes[v.modeFilter-1]
+	}
+	result := "all"
+	if v.resultFilter == game.StatusWon {
+		result = "won"
+	} else if v.resultFilter == game.StatusLost {
+		result = "lost"
+	}
+	filters := fmt.Sprintf("%s  Sort: %-10s  %s  Mode: %-24.24s  %s  Result: %-4s",
+		v.hotkeyLabel('s'), historySortOrderNames[v.sortOrder], v.hotkeyLabel('m'), mode, v.hotkeyLabel('r'), result)
+	screen.PutStrStyled(x, y+1, filters, palette.Border)
+	screen.PutStrStyled(x, y+2, "ENTER  Replay   ESC  Back", palette.ExitText)
+}
+
+// Amount of rows fitting on a single screen.
+func (v *HistoryView) pageSize() int {
+	_, screenHeight := v.ui.screen.Size()
+	return max(screenHeight-historyChromeHeight, 1)
+}
+
+func (v *HistoryView) moveCursor(delta int) {
+	if len(v.rows) == 0 {
+		return
+	}
+
+	v.cursor = max(0, min(v.cursor+delta, len(v.rows)-1))
+	if v.cursor < v.scroll {
+		v.scroll = v.cursor
+	} else if v.cursor >= v.scroll+v.pageSize() {
+		v.scroll = v.cursor - v.pageSize() + 1
+	}
+}
+
+// Applies filters and sort order to all records, resetting the cursor.
+func (v *HistoryView) refreshRows() {
+	v.rows = make([]game.GameRecord, 0, len(v.records))
+	for _, record := range v.records {
+		if v.modeFilter > 0 && record.Rules.Name() != v.modes[v.modeFilter-1] {
+			continue
+		}
+		if v.resultFilter != game.StatusReady && record.Result != v.resultFilter {
+			continue
+		}
+		v.rows = append(v.rows, record)
+	}
+
+	// Records are stored chronologically, stable sort keeps newer ones first on ties
+	slices.Reverse(v.rows)
+	switch v.sortOrder {
+	case historyByTime:
+		slices.SortStableFunc(v.rows, func(a, b game.GameRecord) int {
+			return compareWinsFirst(a, b, func() int { return cmp.Compare(a.Time, b.Time) })
+		})
+	case historyByThreeBVPerSecond:
+		slices.SortStableFunc(v.rows, func(a, b game.GameRecord) int {
+			return compareWinsFirst(a, b, func() int { return cmp.Compare(b.ThreeBVPerSecond(), a.ThreeBVPerSecond()) })
+		})
+	}
+
+	v.cursor = 0
+	v.scroll = 0
+}
+
+func (v *HistoryView) openReplay() {
+	if len(v.rows) == 0 || v.rows[v.cursor].Board == nil {
+		return
+	}
+
+	v.ui.pushView(newReplayView(v.ui, v.rows[v.cursor].Board))
+}
+
+// Lost games have no meaningful time, so they always go after the won ones.
+func compareWinsFirst(a, b game.GameRecord, compare func() int) int {
+	aWon, bWon := a.Result == game.StatusWon, b.Result == game.StatusWon
+	if aWon != bWon {
+		if aWon {
+			return -1
+		}
+		return 1
+	}
+
+	return compare()
+}
@@ -0,0 +1,424 @@
+package ui
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/borogk/hsweeper/game"
+	"github.com/gdamore/tcell/v2"
+)
+
+type (
+	// Action is something a key can be bound to.
+	Action string
+
+	// KeyBindingsConfig is the contents of the key bindings config file.
+	// Bindings override the preset per action, listing all keys of the action.
+	KeyBindingsConfig struct {
+		Preset   string
+		Bindings map[Action][]string
+	}
+
+	// KeyBindings maps keys to actions.
+	KeyBindings struct {
+		preset  string
+		keys    map[Action][]string
+		actions map[keyStroke]Action
+	}
+
+	// Key press as reported by tcell, runes are kept in lower case.
+	keyStroke struct {
+		key  tcell.Key
+		rune rune
+	}
+)
+
+const (
+	ActionUp        Action = "up"
+	ActionDown      Action = "down"
+	ActionLeft      Action = "left"
+	ActionRight     Action = "right"
+	ActionUpLeft    Action = "up-left"
+	ActionUpRight   Action = "up-right"
+	ActionDownLeft  Action = "down-left"
+	ActionDownRight Action = "down-right"
+	ActionPageUp    Action = "page-up"
+	ActionPageDown  Action = "page-down"
+	ActionPageLeft  Action = "page-left"
+	ActionPageRight Action = "page-right"
+	ActionEdgeUp    Action = "edge-up"
+	ActionEdgeDown  Action = "edge-down"
+	ActionEdgeLeft  Action = "edge-left"
+	ActionEdgeRight Action = "edge-right"
+	ActionNextCell  Action = "next-unrevealed"
+	ActionPrevCell  Action = "previous-unrevealed"
+	ActionNextNum   Action = "next-number"
+	ActionPrevNum   Action = "previous-number"
+	ActionHeart     Action = "nearest-heart"
+	ActionGoTo      Action = "go-to"
+	ActionMinimap   Action = "minimap"
+	ActionDensity   Action = "density"
+	ActionPrimary   Action = "action"
+	ActionReveal    Action = "reveal"
+	ActionFlag      Action = "flag"
+	ActionQuestion  Action = "question"
+	ActionClear     Action = "clear"
+	ActionExport    Action = "export"
+	ActionCommand   Action = "command"
+	ActionRetry     Action = "retry"
+	ActionPause     Action = "pause"
+	ActionHelp      Action = "help"
+	ActionBack      Action = "back"
+)
+
+// Actions lists all actions in order of display, along with their descriptions.
+var Actions = []struct {
+	Action      Action
+	Description string
+}{
+	{ActionUp, "Move up"},
+	{ActionDown, "Move down"},
+	{ActionLeft, "Move left"},
+	{ActionRight, "Move right"},
+	{ActionUpLeft, "Move up-left"},
+	{ActionUpRight, "Move up-right"},
+	{ActionDownLeft, "Move down-left"},
+	{ActionDownRight, "Move down-right"},
+	{ActionPageUp, "Move a screen up"},
+	{ActionPageDown, "Move a screen down"},
+	{ActionPageLeft, "Move a screen left"},
+	{ActionPageRight, "Move a screen right"},
+	{ActionEdgeUp, "Jump to the top edge"},
+	{ActionEdgeDown, "Jump to the bottom edge"},
+	{ActionEdgeLeft, "Jump to the left edge"},
+	{ActionEdgeRight, "Jump to the right edge"},
+	{ActionNextCell, "Jump to the next unrevealed cell"},
+	{ActionPrevCell, "Jump to the previous unrevealed cell"},
+	{ActionNextNum, "Jump to the next unresolved number"},
+	{ActionPrevNum, "Jump to the previous unresolved number"},
+	{ActionHeart, "Jump to the nearest heart"},
+	{ActionGoTo, "Go to column and row"},
+	{ActionMinimap, "Toggle minimap"},
+	{ActionDensity, "Change cell width"},
+	{ActionPrimary, "Action key (select in menu)"},
+	{ActionReveal, "Reveal cell"},
+	{ActionFlag, "Toggle flag"},
+	{ActionQuestion, "Toggle question mark"},
+	{ActionClear, "Clear flag or question mark"},
+	{ActionExport, "Export board code"},
+	{ActionCommand, "Open command line"},
+	{ActionRetry, "Retry the same layout after a loss"},
+	{ActionPause, "Pause"},
+	{ActionHelp, "Show help"},
+	{ActionBack, "Back"},
+}
+
+// Every preset keeps arrows and the keys common for all menus, so switching presets never locks anyone out.
+var commonBindings = map[Action][]string{
+	ActionUp:       {"Up"},
+	ActionDown:     {"Down"},
+	ActionLeft:     {"Left"},
+	ActionRight:    {"Right"},
+	ActionNextCell: {"Tab"},
+	ActionPrevCell: {"Backtab"},
+	ActionPrimary:  {"Space", "Enter"},
+	ActionClear:    {"Delete", "Backspace"},
+	ActionPause:    {"Pause"},
+	ActionHelp:     {"F1", "?"},
+	ActionBack:     {"Esc"},
+}
+
+// KeyBindingsPresets lists available presets by name, on top of common bindings.
+var KeyBindingsPresets = map[string]map[Action][]string{
+	"arrows": {
+		ActionPageUp:    {"PgUp"},
+		ActionPageDown:  {"PgDn"},
+		ActionPageLeft:  {"Home"},
+		ActionPageRight: {"End"},
+		ActionEdgeUp:    {"{"},
+		ActionEdgeDown:  {"}"},
+		ActionEdgeLeft:  {"["},
+		ActionEdgeRight: {"]"},
+		ActionNextNum:   {">"},
+		ActionPrevNum:   {"<"},
+		ActionHeart:     {"i"},
+		ActionGoTo:      {"g"},
+		ActionMinimap:   {"m"},
+		ActionDensity:   {"v"},
+		ActionReveal:    {"r"},
+		ActionFlag:      {"f"},
+		ActionQuestion:  {"q"},
+		ActionExport:    {"e"},
+		ActionCommand:   {":"},
+		ActionRetry:     {"t"},
+		ActionPause:     {"p"},
+	},
+	"vim": {
+		ActionUp:        {"k"},
+		ActionDown:      {"j"},
+		ActionLeft:      {"h"},
+		ActionRight:     {"l"},
+		ActionUpLeft:    {"y"},
+		ActionUpRight:   {"u"},
+		ActionDownLeft:  {"b"},
+		ActionDownRight: {"n"},
+		ActionPageUp:    {"PgUp"},
+		ActionPageDown:  {"PgDn"},
+		ActionPageLeft:  {"Home"},
+		ActionPageRight: {"End"},
+		ActionEdgeUp:    {"{"},
+		ActionEdgeDown:  {"}"},
+		ActionEdgeLeft:  {"0"},
+		ActionEdgeRight: {"$"},
+		ActionNextNum:   {">"},
+		ActionPrevNum:   {"<"},
+		ActionHeart:     {"i"},
+		ActionGoTo:      {"g"},
+		ActionMinimap:   {"m"},
+		ActionDensity:   {"v"},
+		ActionReveal:    {"r"},
+		ActionFlag:      {"f"},
+		ActionQuestion:  {"q"},
+		ActionClear:     {"x"},
+		ActionExport:    {"e"},
+		ActionCommand:   {":"},
+		ActionRetry:     {"t"},
+		ActionPause:     {"p"},
+	},
+	"wasd": {
+		ActionUp:        {"w"},
+		ActionDown:      {"s"},
+		ActionLeft:      {"a"},
+		ActionRight:     {"d"},
+		ActionUpLeft:    {"q"},
+		ActionUpRight:   {"e"},
+		ActionDownLeft:  {"z"},
+		ActionDownRight: {"c"},
+		ActionPageUp:    {"PgUp"},
+		ActionPageDown:  {"PgDn"},
+		ActionPageLeft:  {"Home"},
+		ActionPageRight: {"End"},
+		ActionEdgeUp:    {"{"},
+		ActionEdgeDown:  {"}"},
+		ActionEdgeLeft:  {"["},
+		ActionEdgeRight: {"]"},
+		ActionNextNum:   {">"},
+		ActionPrevNum:   {"<"},
+		ActionHeart:     {"i"},
+		ActionGoTo:      {"j"},
+		ActionMinimap:   {"m"},
+		ActionDensity:   {"v"},
+		ActionReveal:    {"r"},
+		ActionFlag:      {"f"},
+		ActionQuestion:  {"g"},
+		ActionExport:    {"x"},
+		ActionCommand:   {":"},
+		ActionRetry:     {"t"},
+		ActionPause:     {"p"},
+	},
+	"numpad": {
+		ActionUp:        {"8"},
+		ActionDown:      {"2"},
+		ActionLeft:      {"4"},
+		ActionRight:     {"6"},
+		ActionUpLeft:    {"7", "Home"},
+		ActionUpRight:   {"9", "PgUp"},
+		ActionDownLeft:  {"1", "End"},
+		ActionDownRight: {"3", "PgDn"},
+		ActionPageUp:    {"/"},
+		ActionPageDown:  {"*"},
+		ActionPageLeft:  {"-"},
+		ActionPageRight: {"+"},
+		ActionEdgeUp:    {"{"},
+		ActionEdgeDown:  {"}"},
+		ActionEdgeLeft:  {"["},
+		ActionEdgeRight: {"]"},
+		ActionNextNum:   {">"},
+		ActionPrevNum:   {"<"},
+		ActionHeart:     {"i"},
+		ActionGoTo:      {"g"},
+		ActionMinimap:   {"m"},
+		ActionDensity:   {"v"},
+		ActionPrimary:   {"5"},
+		ActionReveal:    {".", "r"},
+		ActionFlag:      {"0", "Insert", "f"},
+		ActionQuestion:  {"q"},
+		ActionExport:    {"e"},
+		ActionCommand:   {":"},
+		ActionRetry:     {"t"},
+		ActionPause:     {"p"},
+	},
+}
+
+// KeyBindingsPresetNames lists preset names in order of display.
+var KeyBindingsPresetNames = []string{"arrows", "vim", "wasd", "numpad"}
+
+// DefaultKeyBindingsPreset is used when config doesn't specify a known preset.
+const DefaultKeyBindingsPreset = "arrows"
+
+// Names of non-printable keys used in config files, everything else is a single symbol.
+var keyNames = map[string][]tcell.Key{
+	"Up":        {tcell.KeyUp},
+	"Down":      {tcell.KeyDown},
+	"Left":      {tcell.KeyLeft},
+	"Right":     {tcell.KeyRight},
+	"Home":      {tcell.KeyHome},
+	"End":       {tcell.KeyEnd},
+	"PgUp":      {tcell.KeyPgUp},
+	"PgDn":      {tcell.KeyPgDn},
+	"Insert":    {tcell.KeyInsert},
+	"Delete":    {tcell.KeyDelete},
+	"Backspace": {tcell.KeyBackspace, tcell.KeyBackspace2},
+	"Enter":     {tcell.KeyEnter},
+	"Esc":       {tcell.KeyEscape},
+	"Tab":       {tcell.KeyTab},
+	"Backtab":   {tcell.KeyBacktab},
+	"Pause":     {tcell.KeyPause},
+	"F1":        {tcell.KeyF1},
+	"F2":        {tcell.KeyF2},
+	"F3":        {tcell.KeyF3},
+	"F4":        {tcell.KeyF4},
+	"F5":        {tcell.KeyF5},
+	"F6":        {tcell.KeyF6},
+	"F7":        {tcell.KeyF7},
+	"F8":        {tcell.KeyF8},
+	"F9":        {tcell.KeyF9},
+	"F10":       {tcell.KeyF10},
+	"F11":       {tcell.KeyF11},
+	"F12":       {tcell.KeyF12},
+}
+
+// DefaultKeyBindingsPath returns default key bindings config path.
+func DefaultKeyBindingsPath() string {
+	return game.DataPath("keys.json")
+}
+
+// LoadKeyBindings loads key bindings from config file, missing or broken file results in the default preset.
+func LoadKeyBindings(path string) *KeyBindings {
+	return NewKeyBindings(LoadKeyBindingsConfig(path))
+}
+
+// LoadKeyBindingsConfig loads key bindings config file as is, missing or broken file results in empty config.
+func LoadKeyBindingsConfig(path string) KeyBindingsConfig {
+	config := KeyBindingsConfig{}
+	if data, err := os.ReadFile(path); err == nil {
+		if json.Unmarshal(data, &config) != nil {
+			config = KeyBindingsConfig{}
+		}
+	}
+
+	return config
+}
+
+// Save persists key bindings config file.
+func (c KeyBindingsConfig) Save(filePath string) error {
+	if err := os.MkdirAll(path.Dir(filePath), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, 0600)
+}
+
+// NewKeyBindings builds key bindings from a preset with per-action overrides. Unknown keys are ignored.
+func NewKeyBindings(config KeyBindingsConfig) *KeyBindings {
+	preset := config.Preset
+	if _, ok := KeyBindingsPresets[preset]; !ok {
+		preset = DefaultKeyBindingsPreset
+	}
+
+	b := &KeyBindings{
+		preset:  preset,
+		keys:    make(map[Action][]string),
+		actions: make(map[keyStroke]Action),
+	}
+	for _, action := range Actions {
+		keys := slices.Concat(commonBindings[action.Action], KeyBindingsPresets[preset][action.Action])
+		if override, ok := config.Bindings[action.Action]; ok {
+			keys = override
+		}
+		b.bind(action.Action, keys)
+	}
+
+	return b
+}
+
+// Preset returns name of the preset the bindings are based on.
+func (b *KeyBindings) Preset() string {
+	return b.preset
+}
+
+// Keys returns names of all keys bound to the action.
+func (b *KeyBindings) Keys(action Action) []string {
+	return b.keys[action]
+}
+
+// Action finds the action bound to the key. Letters are matched regardless of case.
+func (b *KeyBindings) Action(key tcell.Key, rune rune) (Action, bool) {
+	if key == tcell.KeyRune {
+		rune = unicode.ToLower(rune)
+	} else {
+		rune = 0
+	}
+
+	action, ok := b.actions[keyStroke{key, rune}]
+	return action, ok
+}
+
+// Binds keys to the action, when a key is listed for several actions the first one wins.
+func (b *KeyBindings) bind(action Action, keys []string) {
+	for _, name := range keys {
+		strokes := parseKeyName(name)
+		if len(strokes) == 0 {
+			continue
+		}
+
+		b.keys[action] = append(b.keys[action], name)
+		for _, stroke := range strokes {
+			if _, ok := b.actions[stroke]; !ok {
+				b.actions[stroke] = action
+			}
+		}
+	}
+}
+
+// Converts a key name from config into key strokes, returns nothing if the name is unknown.
+func parseKeyName(name string) []keyStroke {
+	if name == "Space" {
+		return []keyStroke{{tcell.KeyRune, ' '}}
+	}
+
+	if keys, ok := keyNames[name]; ok {
+		strokes := make([]keyStroke, 0, len(keys))
+		for _, key := range keys {
+			strokes = append(strokes, keyStroke{key, 0})
+		}
+		return strokes
+	}
+
+	if runes := []rune(name); len(runes) == 1 && unicode.IsPrint(runes[0]) {
+		return []keyStroke{{tcell.KeyRune, unicode.ToLower(runes[0])}}
+	}
+
+	return nil
+}
+
+// Formats key names for display, e.g. "Space / Enter".
+func formatKeyNames(names []string) string {
+	display := make([]string, 0, len(names))
+	for _, name := range names {
+		if len([]rune(name)) == 1 {
+			name = strings.ToUpper(name)
+		}
+		display = append(display, name)
+	}
+	return strings.Join(display, " / ")
+}
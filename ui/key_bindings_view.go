@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// KeyBindingsView is a reference of currently active key bindings.
+type KeyBindingsView struct {
+	ui *Ui
+}
+
+const keyBindingsWidth = 72
+
+func newKeyBindingsView(ui *Ui) *KeyBindingsView {
+	return &KeyBindingsView{ui: ui}
+}
+
+func (v *KeyBindingsView) OnActivate() {
+
+}
+
+func (v *KeyBindingsView) OnDeactivate() {
+
+}
+
+func (v *KeyBindingsView) OnInput(key tcell.Key, rune rune) {
+	v.ui.popView()
+}
+
+func (v *KeyBindingsView) ContentSize() (width, height int) {
+	return keyBindingsWidth, len(Actions) + 7
+}
+
+func (v *KeyBindingsView) Draw(screen tcell.Screen) {
+	screenWidth, screenHeight := screen.Size()
+	contentWidth, contentHeight := v.ContentSize()
+	palette := defaultPalette
+
+	x := (screenWidth - contentWidth) / 2
+	y := (screenHeight - contentHeight) / 2
+
+	screen.PutStrStyled(x, y, fmt.Sprintf("Key bindings (preset: %s)", v.ui.keys.Preset()), palette.PlainText)
+	y += 2
+
+	for _, action := range Actions {
+		keys := v.ui.keys.Keys(action.Action)
+		style := palette.PlainText
+		if len(keys) == 0 {
+			style = palette.Border
+		}
+
+		line := fmt.Sprintf("%-30s  %-*.*s", action.Description, keyBindingsWidth-32, keyBindingsWidth-32, formatKeyNames(keys))
+		screen.PutStrStyled(x, y, line, style)
+		y++
+	}
+
+	screen.PutStrStyled(x, y+1, fmt.Sprintf("%-*.*s", keyBindingsWidth, keyBindingsWidth, "Config: "+DefaultKeyBindingsPath()), palette.Border)
+	screen.PutStrStyled(x, y+3, "ESC  Back", palette.ExitText)
+}
@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/borogk/hsweeper/game"
+	"github.com/gdamore/tcell/v2"
+)
+
+// LeaderboardView displays the fastest wins of each game mode.
+type LeaderboardView struct {
+	ui              *Ui
+	leaderboardPath string
+	leaderboard     *game.Leaderboard
+	modes           []string
+	modeIndex       int
+	highlight       int
+}
+
+const maxPlayerNameWidth = 16
+
+var leaderboardHeader = fmt.Sprintf(" #  %-*s  %10s  %5s  %-10s   ", maxPlayerNameWidth, "Name", "Time", "3BV/s", "Date")
+
+// Creates a leaderboard view starting with specified mode, highlight is a rank of an entry to stand out or -1.
+func newLeaderboardView(ui *Ui, leaderboardPath, mode string, highlight int) *LeaderboardView {
+	view := &LeaderboardView{
+		ui:              ui,
+		leaderboardPath: leaderboardPath,
+		highlight:       highlight,
+	}
+	view.load()
+	view.modeIndex = max(slices.Index(view.modes, mode), 0)
+	return view
+}
+
+func (v *LeaderboardView) OnActivate() {
+
+}
+
+func (v *LeaderboardView) OnDeactivate() {
+
+}
+
+func (v *LeaderboardView) OnInput(key tcell.Key, rune rune) {
+	switch key {
+	case tcell.KeyLeft:
+		v.modeIndex = (v.modeIndex - 1 + len(v.modes)) % len(v.modes)
+		v.highlight = -1
+	case tcell.KeyRight:
+		v.modeIndex = (v.modeIndex + 1) % len(v.modes)
+		v.highlight = -1
+	default:
+		v.ui.popView()
+	}
+}
+
+func (v *LeaderboardView) ContentSize() (width, height int) {
+	return len(leaderboardHeader), game.LeaderboardSize + 9
+}
+
+func (v *LeaderboardView) Draw(screen tcell.Screen) {
+	screenWidth, screenHeight := screen.Size()
+	contentWidth, contentHeight := v.ContentSize()
+	palette := defaultPalette
+
+	x := (screenWidth - contentWidth) / 2
+	y := (screenHeight - contentHeight) / 2
+
+	mode := v.modes[v.modeIndex]
+	screen.PutStrStyled(x, y, "Leaderboard", palette.PlainText)
+	screen.PutStrStyled(x, y+2, fmt.Sprintf("%-*s", contentWidth, glyphs.Previous+" "+mode+" "+glyphs.Next), palette.ClassicGameText)
+	screen.PutStrStyled(x, y+4, leaderboardHeader, palette.Border)
+	y += 5
+
+	table := v.leaderboard.Table(mode)
+	for rank := 0; rank < game.LeaderboardSize; rank++ {
+		line := fmt.Sprintf("%2d", rank+1)
+		style := palette.PlainText
+		if rank < len(table) {
+			entry := table[rank]
+			lives := ""
+			if entry.UsedLives {
+				lives = glyphs.Heart
+			}
+			line += fmt.Sprintf("  %-*.*s  %10s  %5.2f  %s  %s",
+				maxPlayerNameWidth,
+				maxPlayerNameWidth,
+				entry.Name,
+				formatDuration(entry.Time),
+				entry.ThreeBVPerSecond,
+				entry.FinishedAt.Format("2006-01-02"),
+				lives,
+			)
+		}
+		if rank == v.highlight {
+			style = palette.WinText
+		}
+		screen.PutStrStyled(x, y, fmt.Sprintf("%-*s", contentWidth, line), style)
+		y++
+	}
+
+	screen.PutStrStyled(x, y+1, glyphs.Heart+" marks wins using extra lives, ranked below clean wins", palette.Border)
+	screen.PutStrStyled(x, y+3, glyphs.KeysLeftRight+"   Switch mode    ESC  Back", palette.ExitText)
+}
+
+// Loads the leaderboard, built-in modes are always listed first, followed by custom ones.
+func (v *LeaderboardView) load() {
+	v.leaderboard = game.LoadLeaderboard(v.leaderboardPath)
+	v.modes = slices.Clone(builtInModes)
+	for _, mode := range v.leaderboard.Modes() {
+		if !slices.Contains(v.modes, mode) {
+			v.modes = append(v.modes, mode)
+		}
+	}
+}
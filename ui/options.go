@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/borogk/hsweeper/game"
+)
+
+// Short names of built-in modes for the command line, full names work too.
+var modeAliases = map[string]string{
+	"expert":         game.ModeExpert,
+	"big":            game.ModeBig,
+	"easy":           game.ModeClassicEasy,
+	"medium":         game.ModeClassicMedium,
+	"classic-expert": game.ModeClassicExpert,
+}
+
+// FindMode looks up rules of a built-in mode or a custom preset by name, ignoring case.
+// H-Big has no size in its rules, as it's sized by the screen.
+func FindMode(name string) (game.Rules, error) {
+	if mode, ok := modeAliases[strings.ToLower(name)]; ok {
+		name = mode
+	}
+
+	for _, mode := range builtInModes {
+		if strings.EqualFold(mode, name) {
+			if mode == game.ModeBig {
+				return game.Rules{Mode: game.ModeBig}, nil
+			}
+			return builtInRules[mode], nil
+		}
+	}
+
+	for _, preset := range game.LoadCustomGames(game.DefaultCustomGamesPath()).Presets {
+		if strings.EqualFold(preset.Mode, name) {
+			return preset, nil
+		}
+	}
+
+	aliases := make([]string, 0, len(modeAliases))
+	for alias := range modeAliases {
+		aliases = append(aliases, alias)
+	}
+	slices.Sort(aliases)
+	return game.Rules{}, fmt.Errorf("unknown mode %q, use one of %s or a custom preset name", name, strings.Join(aliases, ", "))
+}
+
+// Validate checks options before the terminal is taken over, so errors stay visible.
+// Rules are held to the same limits as in the custom game dialog.
+func (o Options) Validate() error {
+	if o.Rules != nil && !(o.Rules.Mode == game.ModeBig && o.Rules.Width == 0) {
+		if err := validateRules(*o.Rules); err != nil {
+			return err
+		}
+	}
+
+	if o.NoSave && o.SavePath != "" {
+		return errors.New("save path is given, but saving is off")
+	}
+
+	if o.Theme != "" {
+		LoadThemes(DefaultThemesPath())
+		names := make([]string, 0, len(Themes))
+		for _, theme := range Themes {
+			names = append(names, theme.Name)
+		}
+		if !slices.Contains(names, o.Theme) {
+			return fmt.Errorf("unknown theme %q, use one of %s", o.Theme, strings.Join(names, ", "))
+		}
+	}
+
+	if _, ok := KeyBindingsPresets[o.Keys]; o.Keys != "" && !ok {
+		data, err := os.ReadFile(o.Keys)
+		if err != nil {
+			return fmt.Errorf("keys must be one of %s or a config file: %w", strings.Join(KeyBindingsPresetNames, ", "), err)
+		}
+		if err := json.Unmarshal(data, &KeyBindingsConfig{}); err != nil {
+			return fmt.Errorf("broken key bindings config %s: %w", o.Keys, err)
+		}
+	}
+
+	return nil
+}
+
+// Returns key bindings chosen in options: a preset keeps the configured overrides, a file replaces the config.
+func (o Options) keyBindings() *KeyBindings {
+	if _, ok := KeyBindingsPresets[o.Keys]; ok {
+		config := LoadKeyBindingsConfig(DefaultKeyBindingsPath())
+		config.Preset = o.Keys
+		return NewKeyBindings(config)
+	} else if o.Keys != "" {
+		return LoadKeyBindings(o.Keys)
+	}
+
+	return LoadKeyBindings(DefaultKeyBindingsPath())
+}
+
+// Returns where the game in progress is kept, empty if saving is off.
+func (o Options) savePath() string {
+	if o.NoSave {
+		return ""
+	} else if o.SavePath != "" {
+		return o.SavePath
+	}
+
+	return game.DefaultSavePath()
+}
+
+// Checks rules against the limits of the custom game dialog.
+func validateRules(rules game.Rules) error {
+	checks := []struct {
+		name               string
+		value              int
+		minValue, maxValue int
+	}{
+		{"width", rules.Width, minCustomGameSize, maxCustomGameSize},
+		{"height", rules.Height, minCustomGameSize, maxCustomGameSize},
+		{"hearts", rules.Hearts, 0, 999},
+		{"lives", rules.Lives, 1, 99},
+	}
+	for _, check := range checks {
+		if _, err := parseCustomGameNumber([]rune(strconv.Itoa(check.value)), check.minValue, check.maxValue); err != nil {
+			return fmt.Errorf("%s %w", check.name, err)
+		}
+	}
+
+	mines := []rune(strconv.Itoa(rules.Mines))
+	if _, err := parseCustomGameMines(mines, rules.Width*rules.Height, rules.FirstClick); err != nil {
+		return fmt.Errorf("mines %w", err)
+	}
+
+	return nil
+}
@@ -1,12 +1,13 @@
 package ui
 
 import (
+	"slices"
+
 	"github.com/borogk/hsweeper/game"
 	"github.com/gdamore/tcell/v2"
 )
 
 // Palette defines colors for all graphics elements in the game.
-// ANSI 256-color palette is used for better terminal compatibility.
 type Palette struct {
 	Blank                 tcell.Style
 	PlainText             tcell.Style
@@ -31,65 +32,117 @@ type Palette struct {
 	RevealUnrevealedFlash tcell.Style
 	RevealFlagFlash       tcell.Style
 	BlastFlash            tcell.Style
+	SolvedNumber          tcell.Style
+	MistakeNumber         tcell.Style
+	WrongFlag             tcell.Style
 	Numbers               []tcell.Style
 }
 
-var defaultPalette = Palette{
-	Blank:                 style(),
-	PlainText:             style(255),
-	Logo:                  style(232, 160),
-	LogoSecondary:         style(232, 26),
-	ExpertGameText:        style(226),
-	BigGameText:           style(84),
-	ClassicGameText:       style(50),
-	ExitText:              style(255),
-	Border:                style(236),
-	ReadyText:             style(255),
-	StatusText:            style(196),
-	LoseText:              style(196),
-	WinText:               style(40),
-	Cursor:                style(232, 84),
-	Unrevealed:            style(236, 234),
-	Flag:                  style(220, 234),
-	Question:              style(255, 234),
-	Heart:                 style(196),
-	RevealedMine:          style(232, 196),
-	UnrevealedMine:        style(196),
-	RevealUnrevealedFlash: style(226, 234),
-	RevealFlagFlash:       style(231, 234),
-	BlastFlash:            style(232, 196),
-	Numbers: []tcell.Style{
-		style(232),
-		style(33),
-		style(84),
-		style(196),
-		style(213),
-		style(88),
-		style(27),
-		style(92),
-		style(244),
-	},
+// Theme is a named set of palettes, with variants for lost and won games.
+type Theme struct {
+	Name    string
+	Palette Palette
+	Lost    Palette
+	Won     Palette
+}
+
+// Themes lists all available themes, built-in ones go first and the very first is used by default.
+var Themes = builtInThemes()
+
+// Palettes of the current theme, replaced when another theme is applied.
+var (
+	defaultPalette = Themes[0].Palette
+	lostPalette    = Themes[0].Lost
+	wonPalette     = Themes[0].Won
+)
+
+// Applies a theme by its name, unknown names fall back to the default theme.
+// Colors are fitted into the amount supported by the terminal, true colors are only kept when the terminal has them.
+func applyTheme(name string, colors int) {
+	theme := Themes[0]
+	for _, t := range Themes {
+		if t.Name == name {
+			theme = t
+		}
+	}
+
+	defaultPalette = theme.Palette.fitColors(colors)
+	lostPalette = theme.Lost.fitColors(colors)
+	wonPalette = theme.Won.fitColors(colors)
 }
 
-var lostPalette = defaultPalette
-var wonPalette = defaultPalette
-
-func init() {
-	lostPalette.Border = style(52)
-	lostPalette.Flag = style(196)
-	lostPalette.Question = style(196)
-	lostPalette.Unrevealed = style(52)
-	lostPalette.UnrevealedMine = style(196)
-	lostPalette.Numbers = []tcell.Style{style(52)}
-
-	wonPalette.Border = style(22)
-	wonPalette.Flag = style(84)
-	wonPalette.Question = style(84)
-	wonPalette.UnrevealedMine = style(84)
-	wonPalette.Numbers = []tcell.Style{style(22)}
-	wonPalette.RevealUnrevealedFlash = style(22)
-	wonPalette.RevealFlagFlash = style(84)
-	wonPalette.BlastFlash = style(232, 84)
+// Returns all styles of the palette by their names, except numbers. Used to read palettes from theme files.
+func (p *Palette) styles() map[string]*tcell.Style {
+	return map[string]*tcell.Style{
+		"Blank":                 &p.Blank,
+		"PlainText":             &p.PlainText,
+		"Logo":                  &p.Logo,
+		"LogoSecondary":         &p.LogoSecondary,
+		"ExpertGameText":        &p.ExpertGameText,
+		"BigGameText":           &p.BigGameText,
+		"ClassicGameText":       &p.ClassicGameText,
+		"ExitText":              &p.ExitText,
+		"Border":                &p.Border,
+		"ReadyText":             &p.ReadyText,
+		"StatusText":            &p.StatusText,
+		"LoseText":              &p.LoseText,
+		"WinText":               &p.WinText,
+		"Cursor":                &p.Cursor,
+		"Unrevealed":            &p.Unrevealed,
+		"Flag":                  &p.Flag,
+		"Question":              &p.Question,
+		"Heart":                 &p.Heart,
+		"RevealedMine":          &p.RevealedMine,
+		"UnrevealedMine":        &p.UnrevealedMine,
+		"RevealUnrevealedFlash": &p.RevealUnrevealedFlash,
+		"RevealFlagFlash":       &p.RevealFlagFlash,
+		"BlastFlash":            &p.BlastFlash,
+		"SolvedNumber":          &p.SolvedNumber,
+		"MistakeNumber":         &p.MistakeNumber,
+		"WrongFlag":             &p.WrongFlag,
+	}
+}
+
+// Returns a copy of the palette with every color replaced by the closest one among the first colors of the terminal.
+// Terminals with true colors keep the palette as is.
+func (p Palette) fitColors(colors int) Palette {
+	if colors >= 1<<24 {
+		return p
+	}
+
+	available := make([]tcell.Color, 0, 256)
+	for i := range min(max(colors, 8), 256) {
+		available = append(available, tcell.PaletteColor(i))
+	}
+	fit := func(color tcell.Color) tcell.Color {
+		if color.IsRGB() || int(color&^tcell.ColorValid) >= len(available) {
+			return tcell.FindColor(color, available)
+		}
+		return color
+	}
+	fitStyle := func(style tcell.Style) tcell.Style {
+		foreground, background, _ := style.Decompose()
+		fittedForeground, fittedBackground := fit(foreground), fit(background)
+
+		// Similar colors may end up the same, making text invisible, then the next closest one is taken
+		if fittedForeground == fittedBackground && foreground != background {
+			others := slices.DeleteFunc(slices.Clone(available), func(c tcell.Color) bool { return c == fittedBackground })
+			fittedForeground = tcell.FindColor(foreground, others)
+		}
+
+		return style.Foreground(fittedForeground).Background(fittedBackground)
+	}
+
+	fitted := p
+	for _, style := range fitted.styles() {
+		*style = fitStyle(*style)
+	}
+	fitted.Numbers = make([]tcell.Style, 0, len(p.Numbers))
+	for _, style := range p.Numbers {
+		fitted.Numbers = append(fitted.Numbers, fitStyle(style))
+	}
+
+	return fitted
 }
 
 // Returns palette fitting for current game status.
@@ -103,19 +156,6 @@ func gamePalette(g *game.Game) Palette {
 	return defaultPalette
 }
 
-func style(colors ...byte) tcell.Style {
-	foreground := byte(232)
-	background := byte(232)
-	if len(colors) > 0 {
-		foreground = colors[0]
-	}
-	if len(colors) > 1 {
-		background = colors[1]
-	}
-
-	return tcell.StyleDefault.Foreground(ansiColor(foreground)).Background(ansiColor(background))
-}
-
 func ansiColor(color byte) tcell.Color {
 	return tcell.ColorValid | tcell.Color(color)
 }
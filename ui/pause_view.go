@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/borogk/hsweeper/game"
+	"github.com/gdamore/tcell/v2"
+)
+
+// PauseView hides the game field, keeping the clock and autosaving stopped until any key is pressed.
+type PauseView struct {
+	ui        *Ui
+	game      *game.Game
+	autoSaver *game.AutoSaver
+}
+
+const pauseWidth = 30
+
+func newPauseView(ui *Ui, g *game.Game, autoSaver *game.AutoSaver) *PauseView {
+	return &PauseView{
+		ui:        ui,
+		game:      g,
+		autoSaver: autoSaver,
+	}
+}
+
+func (v *PauseView) OnActivate() {
+	v.game.PauseClock()
+	v.autoSaver.Suspend()
+}
+
+func (v *PauseView) OnDeactivate() {
+	v.game.ResumeClock()
+	v.autoSaver.Resume()
+}
+
+func (v *PauseView) OnInput(key tcell.Key, rune rune) {
+	v.ui.popView()
+}
+
+func (v *PauseView) ContentSize() (width, height int) {
+	return pauseWidth, 5
+}
+
+func (v *PauseView) Draw(screen tcell.Screen) {
+	screenWidth, screenHeight := screen.Size()
+	contentWidth, contentHeight := v.ContentSize()
+	palette := defaultPalette
+
+	x := (screenWidth - contentWidth) / 2
+	y := (screenHeight - contentHeight) / 2
+
+	screen.PutStrStyled(x, y, "Paused", palette.PlainText)
+	status := fmt.Sprintf("%-*.*s%s", pauseWidth-10, pauseWidth-11, v.game.Rules().Name(), formatDuration(v.game.Elapsed()))
+	screen.PutStrStyled(x, y+2, status, palette.ReadyText)
+	screen.PutStrStyled(x, y+4, "Press any key to resume", palette.ExitText)
+}
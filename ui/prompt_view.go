@@ -0,0 +1,84 @@
+package ui
+
+import (
+	"fmt"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// PromptView asks to type in a single line of text.
+type PromptView struct {
+	ui       *Ui
+	title    string
+	text     []rune
+	message  string
+	onSubmit func(text string) error
+}
+
+const promptWidth = 64
+
+// Creates a prompt, onSubmit is called on ENTER and may return an error to keep the prompt open.
+func newPromptView(ui *Ui, title, text string, onSubmit func(text string) error) *PromptView {
+	return &PromptView{
+		ui:       ui,
+		title:    title,
+		text:     []rune(text),
+		onSubmit: onSubmit,
+	}
+}
+
+func (v *PromptView) OnActivate() {
+
+}
+
+func (v *PromptView) OnDeactivate() {
+
+}
+
+func (v *PromptView) OnInput(key tcell.Key, rune rune) {
+	switch key {
+	case tcell.KeyEscape:
+		v.ui.popView()
+	case tcell.KeyEnter:
+		if err := v.onSubmit(string(v.text)); err != nil {
+			v.message = err.Error()
+		}
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(v.text) > 0 {
+			v.text = v.text[:len(v.text)-1]
+		}
+		v.message = ""
+	case tcell.KeyCtrlU:
+		v.text = nil
+		v.message = ""
+	case tcell.KeyRune:
+		if unicode.IsPrint(rune) {
+			v.text = append(v.text, rune)
+			v.message = ""
+		}
+	}
+}
+
+func (v *PromptView) ContentSize() (width, height int) {
+	return promptWidth, 5
+}
+
+func (v *PromptView) Draw(screen tcell.Screen) {
+	screenWidth, screenHeight := screen.Size()
+	contentWidth, contentHeight := v.ContentSize()
+	palette := defaultPalette
+
+	x := (screenWidth - contentWidth) / 2
+	y := (screenHeight - contentHeight) / 2
+
+	// Only the tail of a long text fits, leave room for the cursor
+	visibleText := v.text
+	if len(visibleText) > contentWidth-3 {
+		visibleText = visibleText[len(visibleText)-contentWidth+3:]
+	}
+
+	screen.PutStrStyled(x, y, fmt.Sprintf("%-*s", contentWidth, v.title), palette.PlainText)
+	screen.PutStrStyled(x, y+2, fmt.Sprintf("> %-*s", contentWidth-2, string(visibleText)+"_"), palette.ReadyText)
+	screen.PutStrStyled(x, y+4, fmt.Sprintf("%-*s", contentWidth, v.message), palette.LoseText)
+}
@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/borogk/hsweeper/game"
+	"github.com/gdamore/tcell/v2"
+)
+
+// ReplayView shows a finished game, stepping through its moves if possible, or just its final board otherwise.
+type ReplayView struct {
+	ui       *Ui
+	board    *game.Game
+	replay   *game.Replay
+	viewport Viewport
+	playing  bool
+	timer    *time.Timer
+	mutex    sync.Mutex
+}
+
+// Slowest and fastest pace of playing the replay, actual pace follows the recorded move times.
+const (
+	minReplayDelay = 50 * time.Millisecond
+	maxReplayDelay = time.Second
+)
+
+// Creates a view of the game captured in the snapshot.
+func newReplayView(ui *Ui, snapshot *game.Snapshot) *ReplayView {
+	view := &ReplayView{ui: ui}
+	if replay, err := game.NewReplay(snapshot); err == nil {
+		view.replay = replay
+		view.board = replay.Game()
+	} else {
+		view.board = game.RestoreGame(snapshot)
+	}
+	return view
+}
+
+func (v *ReplayView) OnActivate() {
+
+}
+
+func (v *ReplayView) OnDeactivate() {
+	v.mutex.Lock()
+	v.stop()
+	v.mutex.Unlock()
+}
+
+func (v *ReplayView) OnInput(key tcell.Key, rune rune) {
+	action, _ := v.ui.keys.Action(key, rune)
+	if action == ActionBack || v.replay == nil {
+		// Playback is stopped on deactivation
+		v.ui.popView()
+		return
+	}
+
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	switch action {
+	case ActionLeft:
+		v.stop()
+		v.seek(v.replay.Step() - 1)
+	case ActionRight:
+		v.stop()
+		v.seek(v.replay.Step() + 1)
+	case ActionPageLeft, ActionEdgeLeft:
+		v.stop()
+		v.seek(0)
+	case ActionPageRight, ActionEdgeRight:
+		v.stop()
+		v.seek(v.replay.Len())
+	case ActionPrimary:
+		v.togglePlaying()
+	}
+}
+
+func (v *ReplayView) ContentSize() (width, height int) {
+	screenWidth, screenHeight := v.ui.screen.Size()
+	width, height = boardSize(fitViewport(v.board, screenWidth, screenHeight-1, v.ui.settings.Density, v.ui.settings.Rulers, false, v.viewport))
+	return width, height + 1
+}
+
+func (v *ReplayView) Draw(screen tcell.Screen) {
+	v.mutex.Lock()
+	defer v.mutex.Unlock()
+
+	palette := gamePalette(v.board)
+
+	status := BoardStatus{"Final board", palette.PlainText, true}
+	hint := "ESC  Back"
+	cursorX, cursorY := -1, -1
+	if v.replay != nil {
+		elapsed := time.Duration(0)
+		if step := v.replay.Step(); step > 0 {
+			move := v.replay.Move(step - 1)
+			elapsed = move.Time
+			cursorX = move.Location % v.board.Width()
+			cursorY = move.Location / v.board.Width()
+		}
+		status = BoardStatus{
+			fmt.Sprintf("Replay   move %d/%d   %s", v.replay.Step(), v.replay.Len(), formatDuration(elapsed)),
+			palette.PlainText,
+			true,
+		}
+		hint = glyphs.KeysLeftRight + " Step   SPACE Play/pause   ESC Back"
+	}
+
+	// One line below the game field is reserved for the hint
+	screenWidth, screenHeight := screen.Size()
+	v.viewport = fitViewport(v.board, screenWidth, screenHeight-1, v.ui.settings.Density, v.ui.settings.Rulers, false, v.viewport)
+	if cursorX >= 0 {
+		v.viewport = v.viewport.follow(cursorX, cursorY)
+	}
+	drawBoard(screen, v.board, v.viewport, palette, AssistOff, status, cursorX, cursorY)
+
+	boardWidth, boardHeight := boardSize(v.viewport)
+	hintX := (screenWidth-boardWidth)/2 + v.viewport.rulerWidth
+	hintY := (screenHeight-boardHeight)/2 + boardHeight
+	screen.PutStrStyled(0, hintY, fmt.Sprintf("%*s", screenWidth, ""), palette.Blank)
+	screen.PutStrStyled(hintX, hintY, hint, palette.Border)
+}
+
+// Moves replay to specified step, the game instance may change when going backwards.
+func (v *ReplayView) seek(step int) {
+	v.replay.Seek(step)
+	v.board = v.replay.Game()
+}
+
+func (v *ReplayView) togglePlaying() {
+	if v.playing {
+		v.stop()
+		return
+	}
+
+	if v.replay.Step() == v.replay.Len() {
+		v.seek(0)
+	}
+	v.playing = true
+	v.scheduleNext()
+}
+
+// Schedules the next move, keeping the pace of the original game.
+func (v *ReplayView) scheduleNext() {
+	step := v.replay.Step()
+	if step >= v.replay.Len() {
+		v.playing = false
+		return
+	}
+
+	delay := minReplayDelay
+	if step > 0 {
+		delay = v.replay.Move(step).Time - v.replay.Move(step-1).Time
+		delay = max(minReplayDelay, min(delay, maxReplayDelay))
+	}
+
+	v.timer = time.AfterFunc(delay, func() {
+		v.ui.post(func() {
+			v.mutex.Lock()
+			if v.playing {
+				v.seek(step + 1)
+				v.scheduleNext()
+			}
+			v.mutex.Unlock()
+			v.ui.refresh()
+		})
+	})
+}
+
+func (v *ReplayView) stop() {
+	v.playing = false
+	if v.timer != nil {
+		v.timer.Stop()
+		v.timer = nil
+	}
+}
@@ -0,0 +1,143 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"time"
+
+	"github.com/borogk/hsweeper/game"
+)
+
+type (
+	// Settings are user preferences, persisted in a config file and applied live.
+	Settings struct {
+		Theme            string
+		Density          Density
+		Rulers           bool
+		QuestionMarks    bool
+		Animations       bool
+		AutosaveInterval int // Seconds
+		Confirmations    bool
+		ConfirmGuesses   bool
+		Assist           Assist
+		IdlePause        int // Seconds without input before the game pauses, 0 means never
+	}
+
+	// Assist defines how much the game helps reading the board.
+	Assist int
+)
+
+const (
+	// AssistOff shows the board as is.
+	AssistOff Assist = iota
+	// AssistSolved dims numbers with all their mines flagged and no other cells left around.
+	AssistSolved
+	// AssistMistakes additionally highlights numbers with more flags around than they allow.
+	AssistMistakes
+)
+
+// Assists lists all assist levels in order of cycling through them, along with their names.
+var Assists = []struct {
+	Assist Assist
+	Name   string
+}{
+	{AssistOff, "off"},
+	{AssistSolved, "dim solved numbers"},
+	{AssistMistakes, "show flag mistakes"},
+}
+
+// MarshalText stores assist level by its name in config files.
+func (a Assist) MarshalText() ([]byte, error) {
+	for _, assist := range Assists {
+		if assist.Assist == a {
+			return []byte(assist.Name), nil
+		}
+	}
+	return nil, fmt.Errorf("unknown assist level %d", a)
+}
+
+// UnmarshalText reads assist level by its name from config files.
+func (a *Assist) UnmarshalText(text []byte) error {
+	for _, assist := range Assists {
+		if assist.Name == string(text) {
+			*a = assist.Assist
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown assist level %q", text)
+}
+
+// AutosaveIntervals lists autosave intervals to choose from, in seconds.
+var AutosaveIntervals = []int{1, 5, 15, 30, 60}
+
+// IdlePauses lists idle times to choose from before pausing the game, in seconds, 0 means never.
+var IdlePauses = []int{0, 30, 60, 120, 300}
+
+// DefaultSettings returns preferences used when nothing is configured.
+func DefaultSettings() Settings {
+	return Settings{
+		Theme:            Themes[0].Name,
+		Density:          DensityNormal,
+		Rulers:           false,
+		QuestionMarks:    true,
+		Animations:       true,
+		AutosaveInterval: 5,
+		Confirmations:    true,
+		ConfirmGuesses:   false,
+		Assist:           AssistOff,
+		IdlePause:        60,
+	}
+}
+
+// DefaultSettingsPath returns default settings config path.
+func DefaultSettingsPath() string {
+	return game.DataPath("settings.json")
+}
+
+// LoadSettings loads settings from config file. Missing or broken file results in default settings,
+// missing values and unknown intervals fall back to their defaults.
+func LoadSettings(path string) *Settings {
+	settings := DefaultSettings()
+	if data, err := os.ReadFile(path); err == nil {
+		if json.Unmarshal(data, &settings) != nil {
+			settings = DefaultSettings()
+		}
+	}
+
+	if !slices.Contains(AutosaveIntervals, settings.AutosaveInterval) {
+		settings.AutosaveInterval = DefaultSettings().AutosaveInterval
+	}
+
+	if !slices.Contains(IdlePauses, settings.IdlePause) {
+		settings.IdlePause = DefaultSettings().IdlePause
+	}
+
+	return &settings
+}
+
+// Save persists settings in config file.
+func (s *Settings) Save(filePath string) error {
+	if err := os.MkdirAll(path.Dir(filePath), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filePath, data, 0600)
+}
+
+// AutosaveEvery returns autosave interval as duration.
+func (s *Settings) AutosaveEvery() time.Duration {
+	return time.Duration(s.AutosaveInterval) * time.Second
+}
+
+// IdlePauseAfter returns idle time before pausing as duration.
+func (s *Settings) IdlePauseAfter() time.Duration {
+	return time.Duration(s.IdlePause) * time.Second
+}
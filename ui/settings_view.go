@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+type (
+	// SettingsView lets changing user preferences, which are saved and applied immediately.
+	SettingsView struct {
+		ui     *Ui
+		rows   []SettingsRow
+		cursor int
+	}
+
+	// SettingsRow is a single preference, changed by cycling through its values.
+	SettingsRow struct {
+		label       string
+		description string
+		value       func() string
+		change      func(step int)
+	}
+)
+
+const settingsWidth = 72
+
+func newSettingsView(ui *Ui) *SettingsView {
+	view := &SettingsView{ui: ui}
+	settings := ui.settings
+
+	view.rows = []SettingsRow{
+		{
+			label:       "Theme",
+			description: "Colors of the whole game, custom themes are loaded from the themes folder",
+			value:       ui.currentTheme,
+			change: func(step int) {
+				// Picking a theme here replaces the one given in the command line
+				i := slices.IndexFunc(Themes, func(theme Theme) bool { return theme.Name == ui.currentTheme() })
+				settings.Theme = Themes[cycle(max(i, 0), step, len(Themes))].Name
+				ui.theme = ""
+				applyTheme(settings.Theme, ui.colors)
+			},
+		},
+		{
+			label:       "Cell density",
+			description: "How wide game cells are, narrow cells fit bigger boards",
+			value:       func() string { return Densities[settings.Density].Name },
+			change: func(step int) {
+				settings.Density = Density(cycle(int(settings.Density), step, len(Densities)))
+			},
+		},
+		{
+			label:       "Rulers",
+			description: "Number rows and columns around the board",
+			value:       func() string { return formatOnOff(settings.Rulers) },
+			change:      func(step int) { settings.Rulers = !settings.Rulers },
+		},
+		{
+			label:       "Key preset",
+			description: "Base layout of key bindings, overrides in keys.json still apply",
+			value:       func() string { return ui.keys.Preset() },
+			change: func(step int) {
+				config := LoadKeyBindingsConfig(DefaultKeyBindingsPath())
+				i := slices.Index(KeyBindingsPresetNames, ui.keys.Preset())
+				config.Preset = KeyBindingsPresetNames[cycle(max(i, 0), step, len(KeyBindingsPresetNames))]
+				_ = config.Save(DefaultKeyBindingsPath())
+				ui.keys = NewKeyBindings(config)
+			},
+		},
+		{
+			label:       "Question marks",
+			description: "Allow marking cells with question marks",
+			value:       func() string { return formatOnOff(settings.QuestionMarks) },
+			change:      func(step int) { settings.QuestionMarks = !settings.QuestionMarks },
+		},
+		{
+			label:       "Animations",
+			description: "Flash cells on reveals and blasts",
+			value:       func() string { return formatOnOff(settings.Animations) },
+			change:      func(step int) { settings.Animations = !settings.Animations },
+		},
+		{
+			label:       "Autosave",
+			description: "How often a game in progress is saved, it's always saved on exit",
+			value:       func() string { return fmt.Sprintf("every %ds", settings.AutosaveInterval) },
+			change: func(step int) {
+				i := slices.Index(AutosaveIntervals, settings.AutosaveInterval)
+				settings.AutosaveInterval = AutosaveIntervals[cycle(max(i, 0), step, len(AutosaveIntervals))]
+			},
+		},
+		{
+			label:       "Idle pause",
+			description: "Pause the game after a while without input, losing focus pauses it too",
+			value: func() string {
+				if settings.IdlePause == 0 {
+					return "off"
+				}
+				return fmt.Sprintf("after %ds", settings.IdlePause)
+			},
+			change: func(step int) {
+				i := slices.Index(IdlePauses, settings.IdlePause)
+				settings.IdlePause = IdlePauses[cycle(max(i, 0), step, len(IdlePauses))]
+			},
+		},
+		{
+			label:       "Confirmations",
+			description: "Ask before abandoning a game or overwriting a save",
+			value:       func() string { return formatOnOff(settings.Confirmations) },
+			change:      func(step int) { settings.Confirmations = !settings.Confirmations },
+		},
+		{
+			label:       "Confirm guesses",
+			description: "Ask before force-revealing a cell, which isn't proven safe",
+			value:       func() string { return formatOnOff(settings.ConfirmGuesses) },
+			change:      func(step int) { settings.ConfirmGuesses = !settings.ConfirmGuesses },
+		},
+		{
+			label:       "Assist",
+			description: "Highlight numbers of the board while playing",
+			value:       func() string { return Assists[settings.Assist].Name },
+			change: func(step int) {
+				settings.Assist = Assist(cycle(int(settings.Assist), step, len(Assists)))
+			},
+		},
+	}
+
+	return view
+}
+
+func (v *SettingsView) OnActivate() {
+
+}
+
+func (v *SettingsView) OnDeactivate() {
+
+}
+
+func (v *SettingsView) OnInput(key tcell.Key, rune rune) {
+	action, _ := v.ui.keys.Action(key, rune)
+	switch action {
+	case ActionDown:
+		v.cursor = (v.cursor + 1) % len(v.rows)
+	case ActionUp:
+		v.cursor = (v.cursor - 1 + len(v.rows)) % len(v.rows)
+	case ActionLeft:
+		v.changeSetting(-1)
+	case ActionRight, ActionPrimary:
+		v.changeSetting(1)
+	case ActionBack:
+		v.ui.popView()
+	}
+}
+
+func (v *SettingsView) ContentSize() (width, height int) {
+	return settingsWidth, len(v.rows) + 8
+}
+
+func (v *SettingsView) Draw(screen tcell.Screen) {
+	screenWidth, screenHeight := screen.Size()
+	contentWidth, contentHeight := v.ContentSize()
+	palette := defaultPalette
+
+	x := (screenWidth - contentWidth) / 2
+	y := (screenHeight - contentHeight) / 2
+
+	screen.PutStrStyled(x, y, "Settings", palette.PlainText)
+	y += 2
+
+	for i, row := range v.rows {
+		marker := " "
+		if i == v.cursor {
+			marker = glyphs.Selected
+		}
+		line := fmt.Sprintf("%s %-16s %s %s %s", marker, row.label, glyphs.Previous, row.value(), glyphs.Next)
+		screen.PutStrStyled(x, y, fmt.Sprintf("%-*s", settingsWidth, line), palette.PlainText)
+		y++
+	}
+
+	description := v.rows[v.cursor].description
+	screen.PutStrStyled(x, y+1, fmt.Sprintf("%-*.*s", settingsWidth, settingsWidth, description), palette.ReadyText)
+	screen.PutStrStyled(x, y+3, fmt.Sprintf("%-*.*s", settingsWidth, settingsWidth, "Config: "+DefaultSettingsPath()), palette.Border)
+	hint := fmt.Sprintf("%s Select   %s Change   ESC Back", glyphs.KeysUpDown, glyphs.KeysLeftRight)
+	screen.PutStrStyled(x, y+5, hint, palette.ExitText)
+}
+
+// Changes the setting under the cursor, saving and showing the result at once.
+func (v *SettingsView) changeSetting(step int) {
+	v.rows[v.cursor].change(step)
+	v.ui.saveSettings()
+	v.ui.fullRefresh()
+}
+
+// Moves index by step, wrapping around the length.
+func cycle(i, step, length int) int {
+	return ((i+step)%length + length) % length
+}
+
+func formatOnOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
@@ -0,0 +1,177 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/borogk/hsweeper/game"
+	"github.com/gdamore/tcell/v2"
+)
+
+// StatsView displays statistics of finished games for each game mode.
+type StatsView struct {
+	ui        *Ui
+	statsPath string
+	summaries []game.ModeSummary
+}
+
+const statsHeader = "Mode                      Played   Won  Win %  Streak  Best  Best time  Avg time  Lives  Hearts   3BV  3BV/s   IOE"
+
+func newStatsView(ui *Ui, statsPath string) *StatsView {
+	return &StatsView{
+		ui:        ui,
+		statsPath: statsPath,
+	}
+}
+
+func (v *StatsView) OnActivate() {
+	v.summaries = loadSummaries(v.statsPath)
+}
+
+func (v *StatsView) OnDeactivate() {
+
+}
+
+func (v *StatsView) OnInput(key tcell.Key, rune rune) {
+	v.ui.popView()
+}
+
+func (v *StatsView) ContentSize() (width, height int) {
+	height = len(v.summaries) + 6
+	if retries, _ := v.retries(); retries > 0 {
+		height += 2
+	}
+	return len(statsHeader), height
+}
+
+func (v *StatsView) Draw(screen tcell.Screen) {
+	screenWidth, screenHeight := screen.Size()
+	contentWidth, contentHeight := v.ContentSize()
+	palette := defaultPalette
+
+	x := (screenWidth - contentWidth) / 2
+	y := (screenHeight - contentHeight) / 2
+
+	screen.PutStrStyled(x, y, "Statistics", palette.PlainText)
+	screen.PutStrStyled(x, y+2, statsHeader, palette.Border)
+	y += 3
+
+	for _, summary := range v.summaries {
+		style := palette.PlainText
+		if slices.Contains(builtInModes, summary.Mode) {
+			style = palette.ClassicGameText
+		}
+
+		screen.PutStrStyled(x, y, formatSummary(summary), style)
+		y++
+	}
+
+	if retries, retriesWon := v.retries(); retries > 0 {
+		screen.PutStrStyled(x, y+1, formatRetries(retries, retriesWon), palette.Border)
+		y += 2
+	}
+
+	screen.PutStrStyled(x, y+1, "ESC  Back", palette.ExitText)
+}
+
+// Totals retries of all modes, which are left out of the table.
+func (v *StatsView) retries() (played, won int) {
+	return totalRetries(v.summaries)
+}
+
+// WriteStats prints the statistics table as plain text, the same one the statistics view shows.
+func WriteStats(w io.Writer, statsPath string) error {
+	summaries := loadSummaries(statsPath)
+	lines := []string{statsHeader}
+	for _, summary := range summaries {
+		lines = append(lines, formatSummary(summary))
+	}
+	if retries, retriesWon := totalRetries(summaries); retries > 0 {
+		lines = append(lines, "", formatRetries(retries, retriesWon))
+	}
+
+	_, err := fmt.Fprintln(w, strings.Join(lines, "\n"))
+	return err
+}
+
+// Summarizes every mode, built-in modes are always listed first, followed by custom ones.
+func loadSummaries(statsPath string) []game.ModeSummary {
+	stats := game.LoadStats(statsPath)
+	modes := slices.Clone(builtInModes)
+	for _, mode := range stats.Modes() {
+		if !slices.Contains(modes, mode) {
+			modes = append(modes, mode)
+		}
+	}
+
+	summaries := make([]game.ModeSummary, 0, len(modes))
+	for _, mode := range modes {
+		summaries = append(summaries, stats.Summary(mode))
+	}
+	return summaries
+}
+
+// Formats a row of the statistics table, matching statsHeader.
+func formatSummary(summary game.ModeSummary) string {
+	played := summary.Played > 0
+	won := summary.Won > 0
+	return fmt.Sprintf("%-24.24s  %6d %5d  %5s  %6d %5d  %9s %9s  %5s  %6s  %4s  %5s  %4s",
+		summary.Mode,
+		summary.Played,
+		summary.Won,
+		formatPercent(summary.WinRate(), played),
+		summary.CurrentStreak,
+		summary.BestStreak,
+		formatDuration(summary.BestTime),
+		formatDuration(summary.AverageTime),
+		formatAverage(summary.AverageLivesUsed, 1, played),
+		formatAverage(summary.AverageHeartsCollected, 1, played),
+		formatAverage(summary.AverageThreeBV, 0, played),
+		formatAverage(summary.BestThreeBVPerSecond, 2, won),
+		formatAverage(summary.AverageEfficiency, 2, won),
+	)
+}
+
+// Totals retries of all modes, which are left out of the table.
+func totalRetries(summaries []game.ModeSummary) (played, won int) {
+	for _, summary := range summaries {
+		played += summary.Retries
+		won += summary.RetriesWon
+	}
+	return played, won
+}
+
+func formatRetries(played, won int) string {
+	return fmt.Sprintf("Retries of the same layout are not counted above: %d played, %d won", played, won)
+}
+
+// Formats game time as minutes and seconds with tenths, zero duration is displayed as a dash.
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return "-"
+	}
+
+	tenths := d.Milliseconds() / 100
+	return fmt.Sprintf("%d:%02d.%d", tenths/600, tenths/10%60, tenths%10)
+}
+
+// Formats a share from 0 to 1 as percent, unknown values are displayed as a dash.
+func formatPercent(value float64, known bool) string {
+	if !known {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.0f%%", value*100)
+}
+
+// Formats an average value with specified precision, unknown values are displayed as a dash.
+func formatAverage(value float64, precision int, known bool) string {
+	if !known {
+		return "-"
+	}
+
+	return fmt.Sprintf("%.*f", precision, value)
+}
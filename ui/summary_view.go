@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/borogk/hsweeper/game"
+	"github.com/gdamore/tcell/v2"
+)
+
+// SummaryView sums up a finished game over its board. For lost games it tells if the fatal move could be avoided.
+type SummaryView struct {
+	Overlay
+	ui      *Ui
+	game    *game.Game
+	onRetry func()
+	lines   []TextLine
+}
+
+const summaryWidth = 48
+
+// Creates a summary, onRetry is called after closing it with the retry key on a lost game.
+func newSummaryView(ui *Ui, g *game.Game, onRetry func()) *SummaryView {
+	view := &SummaryView{ui: ui, game: g, onRetry: onRetry}
+	view.lines = view.summaryLines()
+	return view
+}
+
+func (v *SummaryView) OnActivate() {
+
+}
+
+func (v *SummaryView) OnDeactivate() {
+
+}
+
+func (v *SummaryView) OnInput(key tcell.Key, rune rune) {
+	v.ui.popView()
+	if action, _ := v.ui.keys.Action(key, rune); action == ActionRetry {
+		v.onRetry()
+	}
+}
+
+func (v *SummaryView) ContentSize() (width, height int) {
+	return summaryWidth + 4, len(v.lines) + 2
+}
+
+func (v *SummaryView) Draw(screen tcell.Screen) {
+	palette := gamePalette(v.game)
+	x, y := drawOverlayBox(screen, summaryWidth, len(v.lines), palette)
+	for i, line := range v.lines {
+		screen.PutStrStyled(x, y+i, line.text, line.style(palette))
+	}
+}
+
+// Composes the summary from game metrics and the analysis of the fatal move.
+func (v *SummaryView) summaryLines() []TextLine {
+	lines := make([]TextLine, 0)
+	add := func(style func(palette Palette) tcell.Style, format string, args ...any) {
+		lines = append(lines, TextLine{fmt.Sprintf(format, args...), style})
+	}
+	plain := func(palette Palette) tcell.Style { return palette.PlainText }
+	hint := func(palette Palette) tcell.Style { return palette.Border }
+	mistake := func(palette Palette) tcell.Style { return palette.LoseText }
+
+	g := v.game
+	if g.Status() == game.StatusWon {
+		add(func(palette Palette) tcell.Style { return palette.WinText }, "Well done!")
+	} else {
+		add(mistake, "GAME OVER")
+	}
+	add(plain, "")
+
+	metrics := g.Metrics()
+	add(plain, "%-14s%s", "Time", formatDuration(metrics.Time))
+	add(plain, "%-14s%.2f", "3BV/s", metrics.ThreeBVPerSecond())
+	add(plain, "%-14s%d", "Lives used", g.LivesUsed())
+	add(plain, "%-14s%d of %d", "Hearts", g.HeartsCollected(), g.Rules().Hearts)
+	add(plain, "%-14s%d", "Clicks", metrics.Clicks)
+
+	// Explain the loss: where the fatal mine was, how avoidable it was and how many flags were wrong
+	if g.Status() == game.StatusLost {
+		add(plain, "")
+		if analysis, ok := g.AnalyzeLoss(); ok {
+			x, y := analysis.Location%g.Width(), analysis.Location/g.Width()
+			add(plain, "%-14scolumn %d, row %d", "Fatal cell", x+1, y+1)
+			switch analysis.Verdict {
+			case game.VerdictForcedGuess:
+				add(plain, "Forced guess, no cell was proven safe.")
+			case game.VerdictUnforcedGuess:
+				add(mistake, "Mistake, it was a guess while %d %s", analysis.SafeCells, plural(analysis.SafeCells, "cell", "cells"))
+				add(mistake, "%s proven safe.", plural(analysis.SafeCells, "was", "were"))
+			case game.VerdictMistake:
+				add(mistake, "Mistake, the cell was certainly a mine.")
+			}
+		} else {
+			add(plain, "The fatal move can't be analyzed,")
+			add(plain, "history of this game is incomplete.")
+		}
+
+		if wrongFlags := v.wrongFlags(); wrongFlags > 0 {
+			add(mistake, "%-14s%d, highlighted on the board", "Wrong flags", wrongFlags)
+		}
+	}
+
+	add(plain, "")
+	if g.Status() == game.StatusLost && g.CanRetry() {
+		if retryKeys := formatKeyNames(v.ui.keys.Keys(ActionRetry)); retryKeys != "" {
+			add(hint, "%s to retry the same layout", retryKeys)
+		}
+	}
+	add(hint, "Press any key to see the board")
+	return lines
+}
+
+// Counts flags put on cells without mines.
+func (v *SummaryView) wrongFlags() int {
+	count := 0
+	for y := range v.game.Height() {
+		for x := range v.game.Width() {
+			if cell := v.game.Cell(x, y); cell.IsFlagged() && !cell.IsMine() {
+				count++
+			}
+		}
+	}
+	return count
+}
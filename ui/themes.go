@@ -0,0 +1,222 @@
+package ui
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/borogk/hsweeper/game"
+	"github.com/gdamore/tcell/v2"
+)
+
+// ThemeConfig is the contents of a theme file, either JSON or TOML.
+// Lost and won palettes start from the same palettes of the base theme, changed by the theme palette and then by their own.
+type ThemeConfig struct {
+	Name    string         // File name is used when empty
+	Base    string         // Theme providing everything not listed here, the default theme when empty
+	Palette map[string]any // Styles by palette field names, numbers are a list of styles
+	Lost    map[string]any
+	Won     map[string]any
+}
+
+//go:embed themes
+var builtInThemeFiles embed.FS
+
+// Order of built-in themes, the first one is the default.
+var builtInThemeNames = []string{"default", "light", "high-contrast", "deuteranopia", "protanopia"}
+
+// DefaultThemesPath returns default folder with custom theme files.
+func DefaultThemesPath() string {
+	return game.DataPath("themes")
+}
+
+// LoadThemes adds custom themes from all JSON and TOML files of the folder to the available ones.
+// Broken files and ones reusing names of other themes are skipped.
+func LoadThemes(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		data, err := os.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+
+		theme, err := parseTheme(entry.Name(), data, Themes)
+		if err == nil && !slices.ContainsFunc(Themes, func(t Theme) bool { return t.Name == theme.Name }) {
+			Themes = append(Themes, theme)
+		}
+	}
+}
+
+// Parses themes shipped with the game, which are expected to always be valid.
+func builtInThemes() []Theme {
+	themes := make([]Theme, 0, len(builtInThemeNames))
+	for _, name := range builtInThemeNames {
+		data, err := builtInThemeFiles.ReadFile("themes/" + name + ".toml")
+		if err != nil {
+			panic(err)
+		}
+
+		theme, err := parseTheme(name+".toml", data, themes)
+		if err != nil {
+			panic(err)
+		}
+		themes = append(themes, theme)
+	}
+
+	return themes
+}
+
+// Parses a theme file, its format is chosen by the extension. Base theme is looked up among provided themes.
+func parseTheme(fileName string, data []byte, themes []Theme) (Theme, error) {
+	config := ThemeConfig{}
+	extension := path.Ext(fileName)
+	switch extension {
+	case ".json":
+		if err := json.Unmarshal(data, &config); err != nil {
+			return Theme{}, err
+		}
+	case ".toml":
+		if err := toml.Unmarshal(data, &config); err != nil {
+			return Theme{}, err
+		}
+	default:
+		return Theme{}, fmt.Errorf("unknown theme format %q", extension)
+	}
+
+	theme := Theme{Name: config.Name}
+	if theme.Name == "" {
+		theme.Name = strings.TrimSuffix(fileName, extension)
+	}
+
+	if len(themes) > 0 {
+		base := themes[0]
+		if config.Base != "" {
+			i := slices.IndexFunc(themes, func(t Theme) bool { return t.Name == config.Base })
+			if i < 0 {
+				return Theme{}, fmt.Errorf("unknown base theme %q", config.Base)
+			}
+			base = themes[i]
+		}
+		theme.Palette, theme.Lost, theme.Won = base.Palette, base.Lost, base.Won
+	}
+
+	var err error
+	if theme.Palette, err = applyPaletteConfig(theme.Palette, config.Palette); err != nil {
+		return Theme{}, err
+	}
+	if theme.Lost, err = applyPaletteConfig(theme.Lost, config.Palette); err != nil {
+		return Theme{}, err
+	}
+	if theme.Lost, err = applyPaletteConfig(theme.Lost, config.Lost); err != nil {
+		return Theme{}, err
+	}
+	if theme.Won, err = applyPaletteConfig(theme.Won, config.Palette); err != nil {
+		return Theme{}, err
+	}
+	if theme.Won, err = applyPaletteConfig(theme.Won, config.Won); err != nil {
+		return Theme{}, err
+	}
+
+	if len(theme.Palette.Numbers) == 0 || len(theme.Lost.Numbers) == 0 || len(theme.Won.Numbers) == 0 {
+		return Theme{}, fmt.Errorf("theme %q has no number styles", theme.Name)
+	}
+
+	return theme, nil
+}
+
+// Returns a copy of the palette with styles listed in config.
+func applyPaletteConfig(palette Palette, config map[string]any) (Palette, error) {
+	// Blank goes first, as other styles take missing background from it
+	if value, ok := config["Blank"]; ok {
+		style, err := parseStyle(value, palette.Blank)
+		if err != nil {
+			return palette, fmt.Errorf("Blank: %w", err)
+		}
+		palette.Blank = style
+	}
+
+	styles := palette.styles()
+	for name, value := range config {
+		switch name {
+		case "Blank":
+			continue
+		case "Numbers":
+			values, ok := value.([]any)
+			if !ok {
+				return palette, fmt.Errorf("Numbers: must be a list of styles")
+			}
+
+			numbers := make([]tcell.Style, 0, len(values))
+			for _, value := range values {
+				style, err := parseStyle(value, palette.Blank)
+				if err != nil {
+					return palette, fmt.Errorf("Numbers: %w", err)
+				}
+				numbers = append(numbers, style)
+			}
+			palette.Numbers = numbers
+		default:
+			target, ok := styles[name]
+			if !ok {
+				return palette, fmt.Errorf("unknown palette style %q", name)
+			}
+
+			style, err := parseStyle(value, palette.Blank)
+			if err != nil {
+				return palette, fmt.Errorf("%s: %w", name, err)
+			}
+			*target = style
+		}
+	}
+
+	return palette, nil
+}
+
+// Parses "foreground" or "foreground on background" style, missing background is taken from the blank style.
+func parseStyle(value any, blank tcell.Style) (tcell.Style, error) {
+	text, ok := value.(string)
+	if !ok {
+		return tcell.StyleDefault, fmt.Errorf("style must be a string")
+	}
+
+	foregroundText, backgroundText, hasBackground := strings.Cut(text, " on ")
+	foreground, err := parseColor(foregroundText)
+	if err != nil {
+		return tcell.StyleDefault, err
+	}
+
+	_, background, _ := blank.Decompose()
+	if hasBackground {
+		if background, err = parseColor(backgroundText); err != nil {
+			return tcell.StyleDefault, err
+		}
+	}
+
+	return tcell.StyleDefault.Foreground(foreground).Background(background), nil
+}
+
+// Parses a color, either ANSI 256-color index or "#rrggbb".
+func parseColor(text string) (tcell.Color, error) {
+	text = strings.TrimSpace(text)
+	if index, err := strconv.Atoi(text); err == nil && index >= 0 && index <= 255 {
+		return ansiColor(byte(index)), nil
+	}
+
+	if strings.HasPrefix(text, "#") && len(text) == 7 {
+		if rgb, err := strconv.ParseUint(text[1:], 16, 32); err == nil {
+			return tcell.NewHexColor(int32(rgb)), nil
+		}
+	}
+
+	return tcell.ColorDefault, fmt.Errorf("unknown color %q", text)
+}
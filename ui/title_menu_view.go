@@ -2,6 +2,8 @@ package ui
 
 import (
 	"fmt"
+	"slices"
+	"unicode"
 
 	"github.com/borogk/hsweeper/game"
 	"github.com/gdamore/tcell/v2"
@@ -11,6 +13,7 @@ type (
 	// TitleMenuItem represents a menu item with its action and appearance.
 	TitleMenuItem struct {
 		action func()
+		remove func() // Optional, removes the item with Delete key
 		text   string
 		style  tcell.Style
 		margin int
@@ -18,10 +21,11 @@ type (
 
 	// TitleMenuView is responsible for title menu input and graphics.
 	TitleMenuView struct {
-		ui        *Ui
-		savedGame *game.Game
-		items     []TitleMenuItem
-		cursor    int
+		ui          *Ui
+		savedGame   *game.Game
+		customGames *game.CustomGames
+		items       []TitleMenuItem
+		cursor      int
 	}
 )
 
@@ -40,7 +44,8 @@ func newTitleMenuView(ui *Ui) *TitleMenuView {
 
 func (v *TitleMenuView) OnActivate() {
 	// Preload the auto-save each time menu is activated
-	v.savedGame = game.LoadGame(game.DefaultSavePath())
+	v.savedGame = game.LoadGame(v.ui.savePath)
+	v.customGames = game.LoadCustomGames(game.DefaultCustomGamesPath())
 	v.refreshMenuItems()
 }
 
@@ -49,35 +54,55 @@ func (v *TitleMenuView) OnDeactivate() {
 }
 
 func (v *TitleMenuView) OnInput(key tcell.Key, rune rune) {
-	switch key {
-	case tcell.KeyDown:
+	action, _ := v.ui.keys.Action(key, rune)
+	switch action {
+	case ActionDown:
 		v.cursor = (v.cursor + 1) % len(v.items)
-	case tcell.KeyUp:
+	case ActionUp:
 		v.cursor = (v.cursor - 1 + len(v.items)) % len(v.items)
-	case tcell.KeyEscape:
+	case ActionBack:
 		v.ui.popView()
-	case tcell.KeyEnter:
+	case ActionPrimary:
 		v.selectMenuItem()
 	default:
-		switch rune {
-		case ' ':
+		// Keys bound to navigation are handled above, presets reuse some of the hotkey letters and digits
+		if key == tcell.KeyRune {
+			v.selectHotkey(unicode.ToLower(rune))
+		} else if key == tcell.KeyDelete {
+			v.removeMenuItem()
+		}
+	}
+}
+
+// Returns the hotkey as shown in the menu, blank if the key is bound to menu navigation and doesn't work.
+func (v *TitleMenuView) hotkeyLabel(hotkey rune) string {
+	action, _ := v.ui.keys.Action(tcell.KeyRune, hotkey)
+	if slices.Contains([]Action{ActionDown, ActionUp, ActionBack, ActionPrimary}, action) {
+		return " "
+	}
+	return string(unicode.ToUpper(hotkey))
+}
+
+// Clicking a menu item selects it.
+func (v *TitleMenuView) OnClick(x, y int, buttons tcell.ButtonMask) {
+	if buttons != tcell.ButtonPrimary {
+		return
+	}
+
+	screenWidth, screenHeight := v.ui.screen.Size()
+	itemsX, itemsY := v.itemsPosition(screenWidth, screenHeight)
+	for i, item := range v.items {
+		if y == itemsY && x >= itemsX-2 && x < itemsX+len([]rune(item.text)) {
+			v.cursor = i
 			v.selectMenuItem()
-		case '1':
-			v.startGame(newExpertGameFactory())
-		case '2':
-			v.startGame(v.newBigGameFactory())
-		case '3':
-			v.startGame(newClassicGameFactory(9, 9, 10))
-		case '4':
-			v.startGame(newClassicGameFactory(16, 16, 40))
-		case '5':
-			v.startGame(newClassicGameFactory(30, 16, 99))
+			return
 		}
+		itemsY += 1 + item.margin
 	}
 }
 
 func (v *TitleMenuView) ContentSize() (width, height int) {
-	height = len(logo) + 2
+	height = v.logoHeight()
 	for _, item := range v.items {
 		height += 1 + item.margin
 	}
@@ -85,6 +110,19 @@ func (v *TitleMenuView) ContentSize() (width, height int) {
 	return len(logo[0]), height
 }
 
+// Returns height taken by the logo, which is hidden when the screen is too short to fit it along with all items.
+func (v *TitleMenuView) logoHeight() int {
+	itemsHeight := 0
+	for _, item := range v.items {
+		itemsHeight += 1 + item.margin
+	}
+
+	if _, screenHeight := v.ui.screen.Size(); screenHeight < itemsHeight+len(logo)+2 {
+		return 0
+	}
+	return len(logo) + 2
+}
+
 func (v *TitleMenuView) Draw(screen tcell.Screen) {
 	screenWidth, screenHeight := screen.Size()
 	contentWidth, contentHeight := v.ContentSize()
@@ -92,7 +130,7 @@ func (v *TitleMenuView) Draw(screen tcell.Screen) {
 
 	logoX := (screenWidth - contentWidth) / 2
 	logoY := (screenHeight - contentHeight) / 2
-	for y, logoLine := range logo {
+	for y, logoLine := range logo[:min(v.logoHeight(), len(logo))] {
 		for x, c := range logoLine {
 			logoStyle := palette.Blank
 			if c == '█' {
@@ -105,12 +143,11 @@ func (v *TitleMenuView) Draw(screen tcell.Screen) {
 		}
 	}
 
-	itemsX := (screenWidth - 23) / 2
-	itemsY := logoY + len(logo) + 2
+	itemsX, itemsY := v.itemsPosition(screenWidth, screenHeight)
 	for i, item := range v.items {
 		screen.PutStrStyled(itemsX, itemsY, item.text, item.style)
 		if i == v.cursor {
-			screen.PutStrStyled(itemsX-2, itemsY, "▶", item.style)
+			screen.PutStrStyled(itemsX-2, itemsY, glyphs.Selected, item.style)
 		} else {
 			screen.PutStrStyled(itemsX-2, itemsY, " ", item.style)
 		}
@@ -118,13 +155,56 @@ func (v *TitleMenuView) Draw(screen tcell.Screen) {
 	}
 }
 
+// Starts the action of a menu item by its hotkey.
+func (v *TitleMenuView) selectHotkey(hotkey rune) {
+	switch hotkey {
+	case '1':
+		v.startNewGame(newBuiltInGameFactory(game.ModeExpert))
+	case '2':
+		v.startNewGame(newBigGameFactory(v.ui))
+	case '3':
+		v.startNewGame(newBuiltInGameFactory(game.ModeClassicEasy))
+	case '4':
+		v.startNewGame(newBuiltInGameFactory(game.ModeClassicMedium))
+	case '5':
+		v.startNewGame(newBuiltInGameFactory(game.ModeClassicExpert))
+	case '6', '7', '8', '9':
+		if i := int(hotkey - '6'); i < len(v.customGames.Presets) {
+			v.startNewGame(newCustomGameFactory(v.customGames.Presets[i]))
+		}
+	case 'n':
+		v.openCustomGame()
+	case 'c':
+		v.promptCode()
+	case 's':
+		v.ui.pushView(newStatsView(v.ui, game.DefaultStatsPath()))
+	case 'l':
+		v.ui.pushView(newLeaderboardView(v.ui, game.DefaultLeaderboardPath(), "", -1))
+	case 'h':
+		v.ui.pushView(newHistoryView(v.ui, game.DefaultStatsPath()))
+	case 'a':
+		v.ui.pushView(newAchievementsView(v.ui, game.DefaultStatsPath(), game.DefaultAchievementsPath()))
+	case 'k':
+		v.ui.pushView(newKeyBindingsView(v.ui))
+	case 'o':
+		v.ui.pushView(newSettingsView(v.ui))
+	}
+}
+
+// Returns where the first menu item is drawn.
+func (v *TitleMenuView) itemsPosition(screenWidth, screenHeight int) (x, y int) {
+	_, contentHeight := v.ContentSize()
+	return (screenWidth - 23) / 2, (screenHeight-contentHeight)/2 + v.logoHeight()
+}
+
 func (v *TitleMenuView) refreshMenuItems() {
-	v.items = make([]TitleMenuItem, 0, 7)
+	v.items = make([]TitleMenuItem, 0, 15+len(v.customGames.Presets))
 
 	if v.savedGame != nil {
 		v.items = append(v.items, TitleMenuItem{
 			text: fmt.Sprintf(
-				"Continue [♥ %d] [mines %d]",
+				"Continue [%s %d] [mines %d]",
+				glyphs.Heart,
 				v.savedGame.LivesRemaining(),
 				v.savedGame.MinesRemaining(),
 			),
@@ -135,29 +215,84 @@ func (v *TitleMenuView) refreshMenuItems() {
 	}
 
 	v.items = append(v.items, TitleMenuItem{
-		text:   " 1   H-Expert",
+		text:   fmt.Sprintf(" %s   H-Expert", v.hotkeyLabel('1')),
 		style:  defaultPalette.ExpertGameText,
-		action: func() { v.startGame(newExpertGameFactory()) },
+		action: func() { v.startNewGame(newBuiltInGameFactory(game.ModeExpert)) },
 	})
 	v.items = append(v.items, TitleMenuItem{
-		text:   " 2   H-Big",
+		text:   fmt.Sprintf(" %s   H-Big", v.hotkeyLabel('2')),
 		style:  defaultPalette.BigGameText,
-		action: func() { v.startGame(v.newBigGameFactory()) },
+		action: func() { v.startNewGame(newBigGameFactory(v.ui)) },
 	})
 	v.items = append(v.items, TitleMenuItem{
-		text:   " 3   Classic Easy",
+		text:   fmt.Sprintf(" %s   Classic Easy", v.hotkeyLabel('3')),
 		style:  defaultPalette.ClassicGameText,
-		action: func() { v.startGame(newClassicGameFactory(9, 9, 10)) },
+		action: func() { v.startNewGame(newBuiltInGameFactory(game.ModeClassicEasy)) },
 	})
 	v.items = append(v.items, TitleMenuItem{
-		text:   " 4   Classic Medium",
+		text:   fmt.Sprintf(" %s   Classic Medium", v.hotkeyLabel('4')),
 		style:  defaultPalette.ClassicGameText,
-		action: func() { v.startGame(newClassicGameFactory(16, 16, 40)) },
+		action: func() { v.startNewGame(newBuiltInGameFactory(game.ModeClassicMedium)) },
 	})
 	v.items = append(v.items, TitleMenuItem{
-		text:   " 5   Classic Expert",
+		text:   fmt.Sprintf(" %s   Classic Expert", v.hotkeyLabel('5')),
 		style:  defaultPalette.ClassicGameText,
-		action: func() { v.startGame(newClassicGameFactory(30, 16, 99)) },
+		action: func() { v.startNewGame(newBuiltInGameFactory(game.ModeClassicExpert)) },
+	})
+	for i, preset := range v.customGames.Presets {
+		hotkey := " "
+		if i < 4 {
+			hotkey = v.hotkeyLabel(rune('6' + i))
+		}
+		v.items = append(v.items, TitleMenuItem{
+			text:   fmt.Sprintf(" %s   %s", hotkey, preset.Mode),
+			style:  defaultPalette.PlainText,
+			action: func() { v.startNewGame(newCustomGameFactory(preset)) },
+			remove: func() { v.removePreset(preset.Mode) },
+		})
+	}
+	v.items = append(v.items, TitleMenuItem{
+		text:   fmt.Sprintf(" %s   Custom game", v.hotkeyLabel('n')),
+		style:  defaultPalette.PlainText,
+		action: func() { v.openCustomGame() },
+		margin: 1,
+	})
+	v.items = append(v.items, TitleMenuItem{
+		text:   fmt.Sprintf(" %s   Play from code", v.hotkeyLabel('c')),
+		style:  defaultPalette.PlainText,
+		action: func() { v.promptCode() },
+	})
+	v.items = append(v.items, TitleMenuItem{
+		text:   fmt.Sprintf(" %s   Statistics", v.hotkeyLabel('s')),
+		style:  defaultPalette.PlainText,
+		action: func() { v.ui.pushView(newStatsView(v.ui, game.DefaultStatsPath())) },
+	})
+	v.items = append(v.items, TitleMenuItem{
+		text:   fmt.Sprintf(" %s   Leaderboard", v.hotkeyLabel('l')),
+		style:  defaultPalette.PlainText,
+		action: func() { v.ui.pushView(newLeaderboardView(v.ui, game.DefaultLeaderboardPath(), "", -1)) },
+	})
+	v.items = append(v.items, TitleMenuItem{
+		text:   fmt.Sprintf(" %s   History", v.hotkeyLabel('h')),
+		style:  defaultPalette.PlainText,
+		action: func() { v.ui.pushView(newHistoryView(v.ui, game.DefaultStatsPath())) },
+	})
+	v.items = append(v.items, TitleMenuItem{
+		text:  fmt.Sprintf(" %s   Achievements", v.hotkeyLabel('a')),
+		style: defaultPalette.PlainText,
+		action: func() {
+			v.ui.pushView(newAchievementsView(v.ui, game.DefaultStatsPath(), game.DefaultAchievementsPath()))
+		},
+	})
+	v.items = append(v.items, TitleMenuItem{
+		text:   fmt.Sprintf(" %s   Key bindings", v.hotkeyLabel('k')),
+		style:  defaultPalette.PlainText,
+		action: func() { v.ui.pushView(newKeyBindingsView(v.ui)) },
+	})
+	v.items = append(v.items, TitleMenuItem{
+		text:   fmt.Sprintf(" %s   Settings", v.hotkeyLabel('o')),
+		style:  defaultPalette.PlainText,
+		action: func() { v.ui.pushView(newSettingsView(v.ui)) },
 		margin: 1,
 	})
 	v.items = append(v.items, TitleMenuItem{
@@ -173,6 +308,54 @@ func (v *TitleMenuView) selectMenuItem() {
 	v.items[v.cursor].action()
 }
 
+// Removes the item under the cursor, if it can be removed.
+func (v *TitleMenuView) removeMenuItem() {
+	if remove := v.items[v.cursor].remove; remove != nil {
+		remove()
+	}
+}
+
+// Starts a new game, asking first if it's going to replace the saved game.
+func (v *TitleMenuView) startNewGame(gameFactory GameFactory) {
+	if v.savedGame == nil {
+		v.startGame(gameFactory)
+		return
+	}
+
+	v.ui.confirm(func() { v.startGame(gameFactory) }, "Start a new game?", "The saved game will be lost.")
+}
+
 func (v *TitleMenuView) startGame(gameFactory GameFactory) {
-	v.ui.pushView(newGameView(v.ui, gameFactory, game.DefaultSavePath()))
+	v.ui.startGame(gameFactory)
+}
+
+// Asks for a board code and starts the game from it.
+func (v *TitleMenuView) promptCode() {
+	v.ui.pushView(newPromptView(v.ui, "Enter board code (ESC to cancel):", "", func(code string) error {
+		if _, err := game.DecodeCode(code); err != nil {
+			return err
+		}
+
+		v.ui.popView()
+		v.startNewGame(newCodeGameFactory(code))
+		return nil
+	}))
+}
+
+// Opens the custom game dialog, which starts the game once it's set up.
+func (v *TitleMenuView) openCustomGame() {
+	v.ui.pushView(newCustomGameView(v.ui, game.DefaultCustomGamesPath(), func(rules game.Rules) {
+		v.startNewGame(newCustomGameFactory(rules))
+	}))
+}
+
+// Removes a custom game preset and refreshes the menu, keeping the cursor in place.
+func (v *TitleMenuView) removePreset(name string) {
+	v.customGames.RemovePreset(name)
+	_ = v.customGames.Save(game.DefaultCustomGamesPath())
+
+	cursor := v.cursor
+	v.refreshMenuItems()
+	v.cursor = min(cursor, len(v.items)-1)
+	v.ui.fullRefresh()
 }
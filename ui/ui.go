@@ -3,8 +3,9 @@ package ui
 import (
 	"fmt"
 	"os"
-	"unicode"
+	"time"
 
+	"github.com/borogk/hsweeper/game"
 	"github.com/gdamore/tcell/v2"
 )
 
@@ -16,6 +17,7 @@ type (
 		// OnDeactivate is called when the view is popped from UI stack.
 		OnDeactivate()
 		// OnInput is called on key press. Special keys and printable symbols are handled in separate parameters.
+		// Symbols are passed as typed, views not interested in letter case should lower it themselves.
 		OnInput(key tcell.Key, rune rune)
 		// ContentSize must accurately return how much space the view requires.
 		// It's needed to properly center content in terminal, as well as to warn when the terminal is too small.
@@ -24,15 +26,95 @@ type (
 		Draw(screen tcell.Screen)
 	}
 
+	// Overlay is embedded into modal views, which are drawn in a box over the view underneath instead of replacing it.
+	// Input still goes only to the top view.
+	Overlay struct{}
+
+	// Implemented by views embedding Overlay.
+	overlayView interface {
+		isOverlay()
+	}
+
+	// MouseView is implemented by views accepting mouse input in addition to keys.
+	MouseView interface {
+		// OnClick is called when all mouse buttons are released, passing every button held during the click.
+		// Coordinates are in screen cells.
+		OnClick(x, y int, buttons tcell.ButtonMask)
+	}
+
+	// PausableView is implemented by views, which pause on their own when the player goes away.
+	PausableView interface {
+		// Pause is called when the terminal loses focus or there is no input for a while.
+		Pause()
+	}
+
 	// Ui encapsulates all game graphics and input.
 	Ui struct {
-		views  []View
-		screen tcell.Screen
+		views        []View
+		screen       tcell.Screen
+		colors       int // Colors used for drawing, may be fewer than the terminal supports
+		keys         *KeyBindings
+		settings     *Settings
+		clickButtons tcell.ButtonMask
+		idleTimer    *time.Timer
+		savePath     string // Where the game in progress is kept, empty when saving is off
+		theme        string // Theme given in the command line, used instead of the configured one and never saved
+		// Commands typed into command lines during the session, oldest first
+		commandHistory []string
+	}
+
+	// Options are given in the command line, they take precedence over settings.
+	Options struct {
+		Display  DisplayMode
+		Rules    *game.Rules // Game to start right away, H-Big without size adapts to the screen
+		Seed     int64       // Seed of the first game started right away, 0 means random
+		SavePath string      // Where the game in progress is kept, empty means the default path
+		NoSave   bool        // Don't keep the game in progress at all
+		Theme    string      // Theme used instead of the configured one, not saved
+		Keys     string      // Key bindings preset, or a config file used instead of the default one
 	}
 )
 
 // NewUiWithTitleMenu creates new UI with title menu as its starting view.
-func NewUiWithTitleMenu() *Ui {
+func NewUiWithTitleMenu(options Options) *Ui {
+	ui := newUi(options)
+	ui.pushView(newTitleMenuView(ui))
+	return ui
+}
+
+// NewUiWithCode creates new UI, which immediately starts the game from a board code.
+// Title menu is put underneath, so quitting the game leads there.
+func NewUiWithCode(code string, options Options) *Ui {
+	ui := NewUiWithTitleMenu(options)
+	ui.startGame(newCodeGameFactory(code))
+	return ui
+}
+
+// NewUiWithGame creates new UI, which immediately starts the game with rules from options.
+// Title menu is put underneath, so quitting the game leads there.
+func NewUiWithGame(options Options) *Ui {
+	ui := NewUiWithTitleMenu(options)
+	factory := newCustomGameFactory(*options.Rules)
+	if options.Rules.Mode == game.ModeBig && options.Rules.Width == 0 {
+		factory = newBigGameFactory(ui)
+	}
+	if options.Seed != 0 {
+		factory = newSeededGameFactory(factory, options.Seed)
+	}
+	ui.startGame(factory)
+	return ui
+}
+
+// NewUiWithReplay creates new UI, which immediately shows the game captured in the snapshot.
+// Title menu is put underneath, so closing the replay leads there.
+func NewUiWithReplay(snapshot *game.Snapshot, options Options) *Ui {
+	ui := NewUiWithTitleMenu(options)
+	ui.pushView(newReplayView(ui, snapshot))
+	return ui
+}
+
+// Creates new UI with an empty view stack.
+func newUi(options Options) *Ui {
 	screen, err := tcell.NewScreen()
 	if err != nil {
 		panic(err)
@@ -42,17 +124,37 @@ func NewUiWithTitleMenu() *Ui {
 	if err != nil {
 		panic(err)
 	}
+	screen.EnableMouse(tcell.MouseButtonEvents)
+	screen.EnableFocus()
+
+	colors := applyDisplayMode(options.Display, screen.Colors())
+	LoadThemes(DefaultThemesPath())
+	settings := LoadSettings(DefaultSettingsPath())
 
 	ui := &Ui{
-		views:  make([]View, 0),
-		screen: screen,
+		views:    make([]View, 0),
+		screen:   screen,
+		colors:   colors,
+		keys:     options.keyBindings(),
+		settings: settings,
+		savePath: options.savePath(),
+		theme:    options.Theme,
 	}
-	ui.pushView(newTitleMenuView(ui))
+	applyTheme(ui.currentTheme(), colors)
 	return ui
 }
 
+// Returns the theme in use, the one given in the command line takes priority over settings.
+func (u *Ui) currentTheme() string {
+	if u.theme != "" {
+		return u.theme
+	}
+	return u.settings.Theme
+}
+
 // Loop processes all input and graphics in a loop.
 func (u *Ui) Loop() {
+	u.resetIdleTimer()
 	for {
 		u.refresh()
 		switch event := u.screen.PollEvent().(type) {
@@ -64,8 +166,71 @@ func (u *Ui) Loop() {
 				// Ctrl-C is the only key command to handle globally.
 				u.exit()
 			} else {
-				u.topView().OnInput(event.Key(), unicode.ToLower(event.Rune()))
+				u.topView().OnInput(event.Key(), event.Rune())
+			}
+			u.resetIdleTimer()
+		case *tcell.EventMouse:
+			u.handleMouse(event)
+			u.resetIdleTimer()
+		case *tcell.EventFocus:
+			if !event.Focused {
+				u.pause()
 			}
+		case *tcell.EventInterrupt:
+			// Work scheduled from other goroutines, so that views are only ever touched by the loop
+			if f, ok := event.Data().(func()); ok {
+				f()
+			}
+		}
+	}
+}
+
+// Accumulates pressed mouse buttons and reports a click once they are all released.
+// This way pressing two buttons together (e.g. left and right) counts as a single click.
+func (u *Ui) handleMouse(event *tcell.EventMouse) {
+	buttons := event.Buttons() & (tcell.ButtonPrimary | tcell.ButtonSecondary | tcell.ButtonMiddle)
+	if buttons != tcell.ButtonNone {
+		u.clickButtons |= buttons
+		return
+	}
+
+	if u.clickButtons != tcell.ButtonNone {
+		clickButtons := u.clickButtons
+		u.clickButtons = tcell.ButtonNone
+		if view, ok := u.topView().(MouseView); ok {
+			x, y := event.Position()
+			view.OnClick(x, y, clickButtons)
+		}
+	}
+}
+
+// Restarts counting idle time, the game pauses if there is no input until the timer fires.
+func (u *Ui) resetIdleTimer() {
+	if u.idleTimer != nil {
+		u.idleTimer.Stop()
+	}
+
+	if u.settings.IdlePause > 0 {
+		u.idleTimer = time.AfterFunc(u.settings.IdlePauseAfter(), func() {
+			u.post(u.pause)
+		})
+	}
+}
+
+// Schedules the function to run in the loop, timers and other goroutines must not touch views directly.
+func (u *Ui) post(f func()) {
+	_ = u.screen.PostEvent(tcell.NewEventInterrupt(f))
+}
+
+// Pauses the top view, or the view under overlays on top of it, if it's able to.
+func (u *Ui) pause() {
+	for i := len(u.views) - 1; i >= 0; i-- {
+		if view, ok := u.views[i].(PausableView); ok {
+			view.Pause()
+			return
+		}
+		if !isOverlay(u.views[i]) {
+			return
 		}
 	}
 }
@@ -75,22 +240,49 @@ func (u *Ui) topView() View {
 	return u.views[len(u.views)-1]
 }
 
-// Refreshes the graphics.
+// Starts a game on top of the current view, it's saved and recorded at the usual places.
+func (u *Ui) startGame(gameFactory GameFactory) {
+	u.pushView(newGameView(
+		u,
+		gameFactory,
+		u.savePath,
+		game.DefaultStatsPath(),
+		game.DefaultLeaderboardPath(),
+		game.DefaultAchievementsPath(),
+	))
+}
+
+// Refreshes the graphics. Overlays are drawn on top of the views underneath, starting from the first regular view.
 func (u *Ui) refresh() {
-	view := u.topView()
+	bottom := len(u.views) - 1
+	for bottom > 0 && isOverlay(u.views[bottom]) {
+		bottom--
+	}
+
+	for _, view := range u.views[bottom:] {
+		if !u.draw(view) {
+			break
+		}
+	}
 
+	u.screen.Show()
+}
+
+// Draws a single view, unless the terminal is too small for it. Returns false if the view didn't fit.
+func (u *Ui) draw(view View) bool {
 	screenWidth, screenHeight := u.screen.Size()
 	contentWidth, contentHeight := view.ContentSize()
 	if contentWidth <= screenWidth && contentHeight <= screenHeight {
 		view.Draw(u.screen)
-	} else {
-		message := fmt.Sprintf("Terminal too small (required %dx%d)", contentWidth, contentHeight)
-		messageX := (screenWidth - len(message)) / 2
-		messageY := screenHeight / 2
-		u.screen.PutStrStyled(messageX, messageY, message, defaultPalette.PlainText)
+		return true
 	}
 
-	u.screen.Show()
+	u.screen.Fill(' ', defaultPalette.Blank)
+	message := fmt.Sprintf("Terminal too small (required %dx%d)", contentWidth, contentHeight)
+	messageX := (screenWidth - len(message)) / 2
+	messageY := screenHeight / 2
+	u.screen.PutStrStyled(messageX, messageY, message, defaultPalette.PlainText)
+	return false
 }
 
 // Refreshes the graphics, making sure the screen is fully wiped first.
@@ -118,6 +310,45 @@ func (u *Ui) popView() {
 	}
 }
 
+func (Overlay) isOverlay() {}
+
+// Checks if the view is drawn over the view underneath.
+func isOverlay(view View) bool {
+	_, ok := view.(overlayView)
+	return ok
+}
+
+// Clears a box in the middle of the screen for overlay content of provided size, drawing a border around it.
+// The box takes 4 more columns and 2 more rows than the content. Returns where the content goes.
+func drawOverlayBox(screen tcell.Screen, contentWidth, contentHeight int, palette Palette) (x, y int) {
+	screenWidth, screenHeight := screen.Size()
+	left := (screenWidth - contentWidth - 4) / 2
+	top := (screenHeight - contentHeight - 2) / 2
+	right := left + contentWidth + 3
+	bottom := top + contentHeight + 1
+
+	for row := top; row <= bottom; row++ {
+		screen.PutStrStyled(left, row, fmt.Sprintf("%*s", contentWidth+4, ""), palette.Blank)
+		screen.Put(left, row, glyphs.BorderVertical, palette.PlainText)
+		screen.Put(right, row, glyphs.BorderVertical, palette.PlainText)
+	}
+	for column := left; column <= right; column++ {
+		screen.Put(column, top, glyphs.BorderHorizontal, palette.PlainText)
+		screen.Put(column, bottom, glyphs.BorderHorizontal, palette.PlainText)
+	}
+	screen.Put(left, top, glyphs.BorderTopLeft, palette.PlainText)
+	screen.Put(right, top, glyphs.BorderTopRight, palette.PlainText)
+	screen.Put(left, bottom, glyphs.BorderBottomLeft, palette.PlainText)
+	screen.Put(right, bottom, glyphs.BorderBottomRight, palette.PlainText)
+
+	return left + 2, top + 1
+}
+
+// Persists settings changed by any view. Failing to save is not a reason to interrupt anything.
+func (u *Ui) saveSettings() {
+	_ = u.settings.Save(DefaultSettingsPath())
+}
+
 // Gracefully exits the program.
 func (u *Ui) exit() {
 	for _, view := range u.views {